@@ -297,6 +297,26 @@ func NewReleaseFromACK(ack *DHCPv4, modifiers ...Modifier) (*DHCPv4, error) {
 	)...)
 }
 
+// NewDeclineFromACK creates a DHCPv4 Decline message from ACK, to be sent when
+// the client finds out that the address it was given is already in use.
+// default Decline message without any Modifier is created as following:
+//  - option Message Type is Decline
+//  - ClientHWAddr is set to ack.ClientHWAddr
+//  - option Requested IP Address is set to ack.YourIPAddr
+//  - option Server Identifier is set to ack's ServerIdentifier
+//  - option Client Identifier is copied from ack, if present
+//
+// See RFC 2131, Section 4.4.1, Table 5.
+func NewDeclineFromACK(ack *DHCPv4, modifiers ...Modifier) (*DHCPv4, error) {
+	return New(PrependModifiers(modifiers,
+		WithMessageType(MessageTypeDecline),
+		WithHwAddr(ack.ClientHWAddr),
+		WithOption(OptRequestedIPAddress(ack.YourIPAddr)),
+		WithOptionCopied(ack, OptionServerIdentifier),
+		WithOptionCopied(ack, OptionClientIdentifier),
+	)...)
+}
+
 // FromBytes decodes a DHCPv4 packet from a sequence of bytes, and returns an
 // error if the packet is not valid.
 func FromBytes(q []byte) (*DHCPv4, error) {
@@ -302,6 +302,30 @@ func TestDHCPv4NewRenewFromAckWithModifier(t *testing.T) {
 	require.Contains(t, req.UserClass(), "linuxboot")
 }
 
+func TestNewDeclineFromACK(t *testing.T) {
+	ack, err := New()
+	require.NoError(t, err)
+	ack.UpdateOption(OptMessageType(MessageTypeAck))
+	ack.UpdateOption(OptServerIdentifier(net.IPv4(192, 168, 0, 1)))
+	ack.YourIPAddr = net.IPv4(192, 168, 0, 10)
+	ack.ClientHWAddr = net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}
+
+	decline, err := NewDeclineFromACK(ack)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeDecline, decline.MessageType())
+	require.Equal(t, ack.ClientHWAddr, decline.ClientHWAddr)
+	require.True(t, decline.RequestedIPAddress().Equal(ack.YourIPAddr))
+	require.True(t, decline.ServerIdentifier().Equal(ack.ServerIdentifier()))
+	require.Nil(t, decline.GetOneOption(OptionClientIdentifier))
+	require.True(t, decline.ClientIPAddr.Equal(net.IPv4zero))
+	require.True(t, decline.YourIPAddr.Equal(net.IPv4zero))
+
+	ack.UpdateOption(OptClientIdentifier([]byte{1, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf}))
+	decline, err = NewDeclineFromACK(ack)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf}, decline.GetOneOption(OptionClientIdentifier))
+}
+
 func TestNewReplyFromRequest(t *testing.T) {
 	discover, err := New()
 	require.NoError(t, err)
@@ -16,6 +16,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"net"
 	"os"
 	"sync"
@@ -31,6 +32,11 @@ const (
 	// DefaultTimeout is the default value for read-timeout if option WithTimeout is not set
 	DefaultTimeout = 5 * time.Second
 
+	// DefaultMaxTimeout is the default upper bound of the read-timeout, which
+	// doubles on each retry, if option WithBackoff is not set. It is the
+	// bound suggested by RFC 2131, Section 4.1.
+	DefaultMaxTimeout = 64 * time.Second
+
 	// DefaultRetries is amount of retries will be done if no answer was received within read-timeout amount of time
 	DefaultRetries = 3
 
@@ -62,6 +68,10 @@ var (
 
 	// ErrNoIfaceHWAddr is returned when NewWithConn is called with nil-value as ifaceHWAddr
 	ErrNoIfaceHWAddr = errors.New("ifaceHWAddr is nil")
+
+	// ErrLeaseDeclined is returned when renewing, rebinding or releasing a lease
+	// that was declined with Client.Decline.
+	ErrLeaseDeclined = errors.New("lease was declined")
 )
 
 // pendingCh is a channel associated with a pending TransactionID.
@@ -137,9 +147,17 @@ type Client struct {
 	ifaceHWAddr net.HardwareAddr
 	conn        net.PacketConn
 	timeout     time.Duration
+	maxTimeout  time.Duration
 	retry       int
 	logger      Logger
 
+	// jitter randomizes the read-timeout of each attempt.
+	jitter func(time.Duration) time.Duration
+
+	// after waits for the read-timeout of each attempt. It is time.After,
+	// except in tests that check the timeouts without waiting for them.
+	after func(time.Duration) <-chan time.Time
+
 	// bufferCap is the channel capacity for each TransactionID.
 	bufferCap int
 
@@ -179,7 +197,10 @@ func new(iface string, conn net.PacketConn, ifaceHWAddr net.HardwareAddr, opts .
 	c := &Client{
 		ifaceHWAddr: ifaceHWAddr,
 		timeout:     DefaultTimeout,
+		maxTimeout:  DefaultMaxTimeout,
 		retry:       DefaultRetries,
+		jitter:      rfc2131Jitter,
+		after:       time.After,
 		serverAddr:  DefaultServers,
 		bufferCap:   defaultBufferCap,
 		conn:        conn,
@@ -305,7 +326,8 @@ func (c *Client) receiveLoop() {
 // ClientOpt is a function that configures the Client.
 type ClientOpt func(c *Client) error
 
-// WithTimeout configures the retransmission timeout.
+// WithTimeout configures the retransmission timeout of the first attempt.
+// It doubles on each retransmission, up to the maximum set by WithBackoff.
 //
 // Default is 5 seconds.
 func WithTimeout(d time.Duration) ClientOpt {
@@ -315,6 +337,24 @@ func WithTimeout(d time.Duration) ClientOpt {
 	}
 }
 
+// WithBackoff configures the retransmission timeouts: the first attempt waits
+// for initial, and each retransmission doubles the timeout up to max, as
+// described in RFC 2131, Section 4.1. If max is less than initial, the
+// timeout does not grow.
+//
+// Each timeout is randomized by up to one second, or a quarter of the timeout
+// if that is less, so that clients started at the same time do not keep
+// retransmitting together.
+//
+// Default is an initial timeout of 5 seconds and a maximum of 64 seconds.
+func WithBackoff(initial, max time.Duration) ClientOpt {
+	return func(c *Client) (err error) {
+		c.timeout = initial
+		c.maxTimeout = max
+		return
+	}
+}
+
 // WithSummaryLogger logs one-line DHCPv4 message summaries when sent & received.
 func WithSummaryLogger() ClientOpt {
 	return func(c *Client) (err error) {
@@ -622,7 +662,7 @@ func (c *Client) SendAndRead(ctx context.Context, dest *net.UDPAddr, p *dhcpv4.D
 			case <-c.done:
 				return ErrNoResponse
 
-			case <-time.After(timeout):
+			case <-c.after(timeout):
 				return errDeadlineExceeded
 
 			case <-ctx.Done():
@@ -647,18 +687,15 @@ func (c *Client) SendAndRead(ctx context.Context, dest *net.UDPAddr, p *dhcpv4.D
 }
 
 func (c *Client) retryFn(fn func(timeout time.Duration) error) error {
-	timeout := c.timeout
-
 	// Each retry takes the amount of timeout at worst.
 	for i := 0; i < c.retry || c.retry < 0; i++ { // TODO: why is this called "retry" if this is "tries" ("retries"+1)?
-		switch err := fn(timeout); err {
+		switch err := fn(c.jitter(c.backoff(i))); err {
 		case nil:
 			// Got it!
 			return nil
 
 		case errDeadlineExceeded:
-			// Double timeout, then retry.
-			timeout *= 2
+			// Retry with a longer timeout.
 
 		default:
 			return err
@@ -667,3 +704,29 @@ func (c *Client) retryFn(fn func(timeout time.Duration) error) error {
 
 	return errDeadlineExceeded
 }
+
+// backoff returns the read-timeout of the given attempt, starting at 0,
+// before randomization.
+func (c *Client) backoff(attempt int) time.Duration {
+	timeout := c.timeout
+	for i := 0; i < attempt && timeout < c.maxTimeout; i++ {
+		timeout *= 2
+	}
+	if timeout > c.maxTimeout && c.maxTimeout >= c.timeout {
+		timeout = c.maxTimeout
+	}
+	return timeout
+}
+
+// rfc2131Jitter randomizes d by up to one second as suggested by RFC 2131,
+// Section 4.1, but by no more than a quarter of d.
+func rfc2131Jitter(d time.Duration) time.Duration {
+	spread := time.Second
+	if d/4 < spread {
+		spread = d / 4
+	}
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + time.Duration(rand.Int63n(int64(2*spread)+1))
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -161,6 +162,15 @@ func TestSendAndRead(t *testing.T) {
 			},
 			want: newPacket(dhcpv4.OpcodeBootReply, [4]byte{0x33, 0x33, 0x33, 0x33}),
 		},
+		{
+			desc: "foreign XID followed by matching XID",
+			send: newPacket(dhcpv4.OpcodeBootRequest, [4]byte{0x33, 0x33, 0x33, 0x33}),
+			server: []*dhcpv4.DHCPv4{
+				newPacket(dhcpv4.OpcodeBootReply, [4]byte{0x44, 0x44, 0x44, 0x44}),
+				newPacket(dhcpv4.OpcodeBootReply, [4]byte{0x33, 0x33, 0x33, 0x33}),
+			},
+			want: newPacket(dhcpv4.OpcodeBootReply, [4]byte{0x33, 0x33, 0x33, 0x33}),
+		},
 		{
 			desc: "discard wrong XID",
 			send: newPacket(dhcpv4.OpcodeBootRequest, [4]byte{0x33, 0x33, 0x33, 0x33}),
@@ -329,3 +339,159 @@ func TestMultipleSendAndRead(t *testing.T) {
 		}
 	}
 }
+
+func TestInform(t *testing.T) {
+	clientRawConn, serverRawConn, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConn := NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort})
+	serverConn := NewBroadcastUDPConn(serverRawConn, &net.UDPAddr{Port: ServerPort})
+
+	dns := []net.IP{{192, 168, 0, 53}}
+	received := make(chan *dhcpv4.DHCPv4, 1)
+	s, err := server4.NewServer("", nil, func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		received <- m
+		ack, err := dhcpv4.NewReplyFromRequest(m,
+			dhcpv4.WithMessageType(dhcpv4.MessageTypeAck),
+			dhcpv4.WithOption(dhcpv4.OptDNS(dns...)),
+		)
+		if err != nil {
+			t.Errorf("NewReplyFromRequest = %v", err)
+			return
+		}
+		_, _ = conn.WriteTo(ack.ToBytes(), peer)
+	}, server4.WithConn(serverConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve()
+	}()
+	defer s.Close()
+
+	mc, err := NewWithConn(clientConn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}, WithRetry(1), WithTimeout(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+
+	localIP := net.IP{192, 168, 0, 10}
+	ack, err := mc.Inform(context.Background(), localIP, dhcpv4.WithRequestedOptions(dhcpv4.OptionDomainNameServer))
+	if err != nil {
+		t.Fatalf("Inform = %v", err)
+	}
+	if got := ack.DNS(); len(got) != 1 || !got[0].Equal(dns[0]) {
+		t.Errorf("DNS = %v, want %v", got, dns)
+	}
+
+	inform := <-received
+	if mt := inform.MessageType(); mt != dhcpv4.MessageTypeInform {
+		t.Errorf("message type = %v, want %v", mt, dhcpv4.MessageTypeInform)
+	}
+	if !inform.ClientIPAddr.Equal(localIP) {
+		t.Errorf("client IP = %v, want %v", inform.ClientIPAddr, localIP)
+	}
+	if sid := inform.ServerIdentifier(); sid != nil {
+		t.Errorf("INFORM must not carry a server identifier, got %v", sid)
+	}
+	if !inform.IsOptionRequested(dhcpv4.OptionDomainNameServer) {
+		t.Errorf("INFORM does not request DNS servers: %v", inform.ParameterRequestList())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for _, tt := range []struct {
+		desc         string
+		initial, max time.Duration
+		want         []time.Duration
+	}{
+		{
+			desc:    "default",
+			initial: DefaultTimeout,
+			max:     DefaultMaxTimeout,
+			want:    []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 64 * time.Second, 64 * time.Second},
+		},
+		{
+			desc:    "RFC 2131",
+			initial: 4 * time.Second,
+			max:     64 * time.Second,
+			want:    []time.Duration{4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, 64 * time.Second, 64 * time.Second},
+		},
+		{
+			desc:    "max less than initial",
+			initial: 4 * time.Second,
+			max:     time.Second,
+			want:    []time.Duration{4 * time.Second, 4 * time.Second},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &Client{}
+			if err := WithBackoff(tt.initial, tt.max)(c); err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				if got := c.backoff(i); got != want {
+					t.Errorf("backoff(%d) = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRFC2131Jitter(t *testing.T) {
+	for _, tt := range []struct {
+		d, spread time.Duration
+	}{
+		{d: 64 * time.Second, spread: time.Second},
+		{d: 4 * time.Second, spread: time.Second},
+		{d: 100 * time.Millisecond, spread: 25 * time.Millisecond},
+		{d: 0, spread: 0},
+	} {
+		for i := 0; i < 100; i++ {
+			if got := rfc2131Jitter(tt.d); got < tt.d-tt.spread || got > tt.d+tt.spread {
+				t.Fatalf("rfc2131Jitter(%v) = %v, want within %v of it", tt.d, got, tt.spread)
+			}
+		}
+	}
+}
+
+func TestSendAndReadBackoff(t *testing.T) {
+	clientRawConn, _, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc, err := NewWithConn(NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort}),
+		net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf},
+		WithRetry(5), WithBackoff(50*time.Millisecond, 200*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+	mc.jitter = func(d time.Duration) time.Duration { return d + time.Millisecond }
+
+	// Nobody answers, and every read-timeout expires right away.
+	var got []time.Duration
+	mc.after = func(d time.Duration) <-chan time.Time {
+		got = append(got, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	pkt := newPacket(dhcpv4.OpcodeBootRequest, [4]byte{0x33, 0x33, 0x33, 0x33})
+	if _, err := mc.SendAndRead(context.Background(), DefaultServers, pkt, nil); err != ErrNoResponse {
+		t.Fatalf("SendAndRead = %v, want %v", err, ErrNoResponse)
+	}
+
+	want := []time.Duration{
+		51 * time.Millisecond,
+		101 * time.Millisecond,
+		201 * time.Millisecond,
+		201 * time.Millisecond,
+		201 * time.Millisecond,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read-timeouts = %v, want %v", got, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"time"
 
 	"github.com/mdlayher/packet"
 	"github.com/u-root/uio/uio"
@@ -52,6 +53,18 @@ type BroadcastRawUDPConn struct {
 	// PacketConn is a raw DGRAM socket.
 	net.PacketConn
 
+	// VerifyChecksums makes ReadFrom drop packets whose IPv4 header
+	// checksum or UDP checksum is wrong, as if they did not match
+	// boundAddr. UDP packets without a checksum are accepted.
+	VerifyChecksums bool
+
+	// DisableUDPChecksum makes WriteTo send UDP packets without a
+	// checksum, for receivers that fail to handle them.
+	DisableUDPChecksum bool
+
+	// fragments holds fragmented IP packets until they can be reassembled.
+	fragments reassembler
+
 	// boundAddr is the address this RawUDPConn is "bound" to.
 	//
 	// Calls to ReadFrom will only return packets destined to this address.
@@ -61,7 +74,9 @@ type BroadcastRawUDPConn struct {
 // NewBroadcastUDPConn returns a PacketConn that marshals and unmarshals UDP
 // packets, sending them to the broadcast MAC at on rawPacketConn.
 //
-// Calls to ReadFrom will only return packets destined to boundAddr.
+// Calls to ReadFrom will only return packets destined to boundAddr. The returned
+// conn is a *BroadcastRawUDPConn, whose VerifyChecksums and DisableUDPChecksum
+// fields may be set before it is used.
 func NewBroadcastUDPConn(rawPacketConn net.PacketConn, boundAddr *net.UDPAddr) net.PacketConn {
 	return &BroadcastRawUDPConn{
 		PacketConn: rawPacketConn,
@@ -83,6 +98,7 @@ func udpMatch(addr *net.UDPAddr, bound *net.UDPAddr) bool {
 //
 // ReadFrom reads raw IP packets and will try to match them against
 // upc.boundAddr. Any matching packets are returned via the given buffer.
+// Fragmented IP packets are reassembled before they are matched.
 func (upc *BroadcastRawUDPConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	ipHdrMaxLen := ipv4MaximumHeaderSize
 	udpHdrLen := udpMinimumSize
@@ -112,10 +128,27 @@ func (upc *BroadcastRawUDPConn) ReadFrom(b []byte) (int, net.Addr, error) {
 
 		ipHdr = ipv4(buf.Consume(int(ipHdr.headerLength())))
 
+		if upc.VerifyChecksums && !ipHdr.isChecksumValid() {
+			continue
+		}
+
 		if ipHdr.transportProtocol() != udpProtocolNumber {
 			continue
 		}
 
+		ipPayloadLen := int(ipHdr.payloadLength())
+		if ipHdr.isFragment() {
+			if !buf.Has(ipPayloadLen) {
+				continue
+			}
+			datagram := upc.fragments.process(ipHdr, buf.Consume(ipPayloadLen), time.Now())
+			if datagram == nil {
+				continue
+			}
+			buf = uio.NewBigEndianBuffer(datagram)
+			ipPayloadLen = len(datagram)
+		}
+
 		if !buf.Has(udpHdrLen) {
 			continue
 		}
@@ -135,8 +168,12 @@ func (upc *BroadcastRawUDPConn) ReadFrom(b []byte) (int, net.Addr, error) {
 		}
 		// Extra padding after end of IP packet should be ignored,
 		// if not dhcp option parsing will fail.
-		dhcpLen := int(ipHdr.payloadLength()) - udpHdrLen
-		return copy(b, buf.Consume(dhcpLen)), srcAddr, nil
+		dhcpLen := ipPayloadLen - udpHdrLen
+		payload := buf.Consume(dhcpLen)
+		if upc.VerifyChecksums && !udpHdr.isChecksumValid(ipHdr.sourceAddress(), ipHdr.destinationAddress(), payload) {
+			continue
+		}
+		return copy(b, payload), srcAddr, nil
 	}
 }
 
@@ -153,6 +190,9 @@ func (upc *BroadcastRawUDPConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 
 	// Using the boundAddr is not quite right here, but it works.
 	pkt := udp4pkt(b, udpAddr, upc.boundAddr)
+	if upc.DisableUDPChecksum {
+		udp(pkt[ipv4MinimumSize:]).setChecksum(0)
+	}
 
 	// Broadcasting is not always right, but hell, what the ARP do I know.
 	return upc.PacketConn.WriteTo(pkt, &packet.Addr{HardwareAddr: BroadcastMac})
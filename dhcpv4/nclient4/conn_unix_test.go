@@ -0,0 +1,220 @@
+// Copyright 2018 the u-root Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.12 && (darwin || freebsd || linux || netbsd || openbsd)
+// +build go1.12
+// +build darwin freebsd linux netbsd openbsd
+
+package nclient4
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/hugelgupf/socketpair"
+)
+
+func TestBroadcastRawUDPConnVerifyChecksums(t *testing.T) {
+	src := &net.UDPAddr{IP: net.IP{192, 168, 0, 1}, Port: ServerPort}
+	dst := &net.UDPAddr{IP: net.IPv4bcast, Port: ClientPort}
+
+	valid := func(payload string) []byte {
+		return udp4pkt([]byte(payload), dst, src)
+	}
+	badIPChecksum := valid("bad ip")
+	badIPChecksum[checksumOff] ^= 0xff
+	badUDPChecksum := valid("bad udp")
+	badUDPChecksum[ipv4MinimumSize+udpchecksum] ^= 0xff
+	badPayload := valid("bad payload")
+	badPayload[len(badPayload)-1] ^= 0x02 // "bad payloaf"
+	noUDPChecksum := valid("no checksum")
+	udp(noUDPChecksum[ipv4MinimumSize:]).setChecksum(0)
+
+	packets := [][]byte{
+		valid("first"),
+		badIPChecksum,
+		badUDPChecksum,
+		badPayload,
+		noUDPChecksum,
+		valid("last"),
+	}
+
+	for _, tt := range []struct {
+		verify bool
+		want   []string
+	}{
+		{verify: false, want: []string{"first", "bad ip", "bad udp", "bad payloaf", "no checksum", "last"}},
+		{verify: true, want: []string{"first", "no checksum", "last"}},
+	} {
+		serverRawConn, clientRawConn, err := socketpair.PacketSocketPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn := NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort}).(*BroadcastRawUDPConn)
+		conn.VerifyChecksums = tt.verify
+
+		for _, p := range packets {
+			if _, err := serverRawConn.WriteTo(p, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, want := range tt.want {
+			b := make([]byte, 1500)
+			n, _, err := conn.ReadFrom(b)
+			if err != nil {
+				t.Fatalf("ReadFrom = %v", err)
+			}
+			if got := string(b[:n]); got != want {
+				t.Errorf("VerifyChecksums=%t: ReadFrom = %q, want %q", tt.verify, got, want)
+			}
+		}
+		serverRawConn.Close()
+		conn.Close()
+	}
+}
+
+// onesComplementSum is a straightforward RFC 1071 sum of b, padded with a zero
+// byte if its length is odd.
+func onesComplementSum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i < len(b); i += 2 {
+		v := uint32(b[i]) << 8
+		if i+1 < len(b) {
+			v |= uint32(b[i+1])
+		}
+		sum += v
+	}
+	for sum > 0xffff {
+		sum = sum&0xffff + sum>>16
+	}
+	return uint16(sum)
+}
+
+func TestBroadcastRawUDPConnWriteToChecksums(t *testing.T) {
+	for _, disable := range []bool{false, true} {
+		rawConn, peerConn, err := socketpair.PacketSocketPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn := NewBroadcastUDPConn(rawConn, &net.UDPAddr{IP: net.IP{192, 168, 0, 10}, Port: ClientPort}).(*BroadcastRawUDPConn)
+		conn.DisableUDPChecksum = disable
+
+		// An odd length exercises the padding of the last byte.
+		payload := []byte("some DHCP payload")
+		if _, err := conn.WriteTo(payload, &net.UDPAddr{IP: net.IP{192, 168, 0, 1}, Port: ServerPort}); err != nil {
+			t.Fatal(err)
+		}
+		pkt := make([]byte, 1500)
+		n, _, err := peerConn.ReadFrom(pkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkt = pkt[:n]
+		rawConn.Close()
+		peerConn.Close()
+
+		ipHdr := pkt[:ipv4MinimumSize]
+		if sum := onesComplementSum(ipHdr); sum != 0xffff {
+			t.Errorf("IPv4 header checksum does not validate, sum %#x", sum)
+		}
+
+		segment := pkt[ipv4MinimumSize:]
+		udpChecksum := uint16(segment[udpchecksum])<<8 | uint16(segment[udpchecksum+1])
+		if disable {
+			if udpChecksum != 0 {
+				t.Errorf("UDP checksum = %#x, want 0 when disabled", udpChecksum)
+			}
+			continue
+		}
+		if udpChecksum == 0 {
+			t.Fatal("UDP checksum is not set")
+		}
+		pseudo := append(append([]byte{}, ipHdr[srcAddr:dstAddr+4]...),
+			0, byte(udpProtocolNumber), byte(len(segment)>>8), byte(len(segment)))
+		if sum := onesComplementSum(append(pseudo, segment...)); sum != 0xffff {
+			t.Errorf("UDP checksum does not validate, sum %#x", sum)
+		}
+	}
+}
+
+// ipv4Fragment returns the fragment of the IPv4 packet pkt that carries the
+// payload bytes [offset, end), with the "more fragments" flag set if more
+// is true.
+func ipv4Fragment(pkt []byte, offset, end int, more bool) []byte {
+	frag := append([]byte{}, pkt[:ipv4MinimumSize]...)
+	frag = append(frag, pkt[ipv4MinimumSize+offset:ipv4MinimumSize+end]...)
+
+	var flags uint8
+	if more {
+		flags = ipv4FlagMoreFragments
+	}
+	hdr := ipv4(frag)
+	hdr.setTotalLength(uint16(len(frag)))
+	hdr.setFlagsFragmentOffset(flags, uint16(offset))
+	hdr.setChecksum(0)
+	hdr.setChecksum(^hdr.calculateChecksum())
+	return frag
+}
+
+func TestBroadcastRawUDPConnFragments(t *testing.T) {
+	src := &net.UDPAddr{IP: net.IP{192, 168, 0, 1}, Port: ServerPort}
+	dst := &net.UDPAddr{IP: net.IPv4bcast, Port: ClientPort}
+
+	payload := make([]byte, 300)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	pkt := udp4pkt(payload, dst, src)
+	ipv4(pkt).encode(&ipv4Fields{
+		IHL:         ipv4MinimumSize,
+		TotalLength: uint16(len(pkt)),
+		ID:          0x1234,
+		TTL:         64,
+		Protocol:    uint8(udpProtocolNumber),
+		SrcAddr:     src.IP.To4(),
+		DstAddr:     dst.IP.To4(),
+	})
+	udpLen := len(pkt) - ipv4MinimumSize
+
+	serverRawConn, clientRawConn, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverRawConn.Close()
+	conn := NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort}).(*BroadcastRawUDPConn)
+	conn.VerifyChecksums = true
+	defer conn.Close()
+
+	// Send the fragments out of order, followed by an unfragmented packet.
+	for _, p := range [][]byte{
+		ipv4Fragment(pkt, 160, udpLen, false),
+		ipv4Fragment(pkt, 0, 160, true),
+		udp4pkt([]byte("after"), dst, src),
+	} {
+		if _, err := serverRawConn.WriteTo(p, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := make([]byte, 1500)
+	n, addr, err := conn.ReadFrom(b)
+	if err != nil {
+		t.Fatalf("ReadFrom = %v", err)
+	}
+	if !bytes.Equal(b[:n], payload) {
+		t.Errorf("ReadFrom = %v, want %v", b[:n], payload)
+	}
+	if got := addr.String(); got != src.String() {
+		t.Errorf("ReadFrom address = %s, want %s", got, src)
+	}
+
+	n, _, err = conn.ReadFrom(b)
+	if err != nil {
+		t.Fatalf("ReadFrom = %v", err)
+	}
+	if got := string(b[:n]); got != "after" {
+		t.Errorf("ReadFrom = %q, want %q", got, "after")
+	}
+}
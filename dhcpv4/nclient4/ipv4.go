@@ -95,6 +95,10 @@ const (
 
 	// ipv4AddressSize is the size, in bytes, of an IPv4 address.
 	ipv4AddressSize = 4
+
+	// ipv4FlagMoreFragments is the "more fragments" bit of the flags
+	// field of an IPv4 packet.
+	ipv4FlagMoreFragments = 1 << 0
 )
 
 // headerLength returns the value of the "header length" field of the ipv4
@@ -144,6 +148,28 @@ func (b ipv4) setChecksum(v uint16) {
 	binary.BigEndian.PutUint16(b[checksumOff:], v)
 }
 
+// id returns the value of the "identification" field of the ipv4 header.
+func (b ipv4) id() uint16 {
+	return binary.BigEndian.Uint16(b[id:])
+}
+
+// flags returns the value of the "flags" field of the ipv4 header.
+func (b ipv4) flags() uint8 {
+	return uint8(binary.BigEndian.Uint16(b[flagsFO:]) >> 13)
+}
+
+// fragmentOffset returns the "fragment offset" field of the ipv4 header, in
+// bytes.
+func (b ipv4) fragmentOffset() uint16 {
+	return binary.BigEndian.Uint16(b[flagsFO:]) << 3
+}
+
+// isFragment reports whether the ipv4 packet is a fragment of a larger
+// datagram.
+func (b ipv4) isFragment() bool {
+	return b.flags()&ipv4FlagMoreFragments != 0 || b.fragmentOffset() != 0
+}
+
 // setFlagsFragmentOffset sets the "flags" and "fragment offset" fields of the
 // ipv4 header.
 func (b ipv4) setFlagsFragmentOffset(flags uint8, offset uint16) {
@@ -156,6 +182,12 @@ func (b ipv4) calculateChecksum() uint16 {
 	return checksum(b[:b.headerLength()], 0)
 }
 
+// isChecksumValid reports whether the checksum field of the ipv4 header
+// matches its contents.
+func (b ipv4) isChecksumValid() bool {
+	return b.calculateChecksum() == 0xffff
+}
+
 // encode encodes all the fields of the ipv4 header.
 func (b ipv4) encode(i *ipv4Fields) {
 	b[versIHL] = (4 << 4) | ((i.IHL / 4) & 0xf)
@@ -237,6 +269,18 @@ func (b udp) calculateChecksum(partialchecksum uint16, totalLen uint16) uint16 {
 	return checksum(b[:udpMinimumSize], xsum)
 }
 
+// isChecksumValid reports whether the checksum field of the udp header matches
+// the header, the given payload and the network-layer pseudo-header built from
+// srcAddr and dstAddr. A zero checksum means that the sender did not compute
+// one, and is always valid.
+func (b udp) isChecksumValid(srcAddr, dstAddr net.IP, payload []byte) bool {
+	if binary.BigEndian.Uint16(b[udpchecksum:]) == 0 {
+		return true
+	}
+	xsum := checksum(payload, pseudoHeaderchecksum(udpProtocolNumber, srcAddr, dstAddr))
+	return b.calculateChecksum(xsum, b.length()) == 0xffff
+}
+
 // encode encodes all the fields of the udp header.
 func (b udp) encode(u *udpFields) {
 	binary.BigEndian.PutUint16(b[udpSrcPort:], u.SrcPort)
@@ -316,7 +360,13 @@ func udp4pkt(packet []byte, dest *net.UDPAddr, src *net.UDPAddr) []byte {
 
 	xsum := checksum(packet, pseudoHeaderchecksum(
 		ipv4hdr.transportProtocol(), ipv4fields.SrcAddr, ipv4fields.DstAddr))
-	udphdr.setChecksum(^udphdr.calculateChecksum(xsum, udphdr.length()))
+	udpChecksum := ^udphdr.calculateChecksum(xsum, udphdr.length())
+	if udpChecksum == 0 {
+		// Zero means "no checksum", so send its one's complement
+		// equivalent instead, as required by RFC 768.
+		udpChecksum = 0xffff
+	}
+	udphdr.setChecksum(udpChecksum)
 
 	hdr.WriteBytes(packet)
 	return hdr.Data()
@@ -17,16 +17,43 @@ type Lease struct {
 	Offer        *dhcpv4.DHCPv4
 	ACK          *dhcpv4.DHCPv4
 	CreationTime time.Time
+
+	// declined is set once the lease was declined, after which it must not be
+	// used anymore.
+	declined bool
+}
+
+// Declined returns whether the lease was declined with Client.Decline. A
+// declined lease cannot be renewed, rebound or released.
+func (l *Lease) Declined() bool {
+	return l.declined
+}
+
+// serverIdentifier returns the identifier of the server that granted the lease.
+// It may differ from the one of the offer after rebinding.
+func (l *Lease) serverIdentifier() net.IP {
+	if sid := l.ACK.ServerIdentifier(); sid != nil {
+		return sid
+	}
+	return l.Offer.ServerIdentifier()
 }
 
 // Release send DHCPv4 release messsage to server, based on specified lease.
-// release is sent as unicast per RFC2131, section 4.4.4.
+// release is addressed to the server identifier of the lease per RFC2131, section 4.4.4.
+//
+// The message is sent over the client's existing connection. With the default raw socket
+// connection, no UDP port is bound and the leased address does not need to be configured on
+// the interface: the message goes out in a broadcast frame, from the address the connection
+// is bound to.
 // Note: some DHCP server requries of using assigned IP address as source IP,
 // use nclient4.WithUnicast to create client for such case.
 func (c *Client) Release(lease *Lease, modifiers ...dhcpv4.Modifier) error {
 	if lease == nil {
 		return fmt.Errorf("lease is nil")
 	}
+	if lease.declined {
+		return ErrLeaseDeclined
+	}
 	req, err := dhcpv4.NewReleaseFromACK(lease.ACK, modifiers...)
 	if err != nil {
 		return fmt.Errorf("fail to create release request,%w", err)
@@ -38,14 +65,49 @@ func (c *Client) Release(lease *Lease, modifiers ...dhcpv4.Modifier) error {
 	return err
 }
 
+// Decline sends a DHCPv4 decline message to the server, for a lease whose address the client found
+// to be already in use, as described in RFC 2131, Section 4.4.1. The message carries the declined
+// address in the Requested IP Address option and is sent to the client's server address, which is
+// broadcast by default. No reply is expected.
+//
+// Once the message is sent, the lease is marked as declined: Renew, Rebind and Release refuse it
+// with ErrLeaseDeclined, and the caller should get a new lease. Decline returns ctx's error without
+// sending anything if ctx is already done.
+func (c *Client) Decline(ctx context.Context, lease *Lease, modifiers ...dhcpv4.Modifier) error {
+	if lease == nil {
+		return fmt.Errorf("lease is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	req, err := dhcpv4.NewDeclineFromACK(lease.ACK, modifiers...)
+	if err != nil {
+		return fmt.Errorf("fail to create decline message,%w", err)
+	}
+	if _, err := c.conn.WriteTo(req.ToBytes(), c.serverAddr); err != nil {
+		return err
+	}
+	c.logger.PrintMessage("sent message:", req)
+	lease.declined = true
+	return nil
+}
+
 // Renew sends a DHCPv4 request to the server to renew the given lease. The renewal information is
 // sourced from the initial offer in the lease, and the ACK of the lease is updated to the ACK of
 // the latest renewal. This avoids issues with DHCP servers that omit information needed to build a
 // completely new lease from their renewal ACK (such as the Windows DHCP Server).
+//
+// Unless the client was configured with a unicast server address by WithServerAddr, the request
+// is unicast to the server that granted the lease, as in the RENEWING state of RFC 2131, Section
+// 4.3.2. If the server rejects the request, the returned error is an *ErrNak, after which the
+// caller should get a new lease. If the server does not answer, see Rebind.
 func (c *Client) Renew(ctx context.Context, lease *Lease, modifiers ...dhcpv4.Modifier) (*Lease, error) {
 	if lease == nil {
 		return nil, fmt.Errorf("lease is nil")
 	}
+	if lease.declined {
+		return nil, ErrLeaseDeclined
+	}
 
 	request, err := dhcpv4.NewRenewFromAck(lease.ACK, dhcpv4.PrependModifiers(modifiers,
 		dhcpv4.WithOption(dhcpv4.OptMaxMessageSize(MaxMessageSize)))...)
@@ -57,8 +119,13 @@ func (c *Client) Renew(ctx context.Context, lease *Lease, modifiers ...dhcpv4.Mo
 	// but sometimes non-compliant servers respond anyway.
 	// Clients are not required to validate this field, but servers are required to
 	// include the server identifier in their Offer per RFC 2131 Section 4.3.1 Table 3.
-	response, err := c.SendAndRead(ctx, c.serverAddr, request, IsAll(
-		IsCorrectServer(lease.Offer.ServerIdentifier()),
+	sid := lease.serverIdentifier()
+	dest := c.serverAddr
+	if sid != nil && dest.IP.Equal(net.IPv4bcast) {
+		dest = &net.UDPAddr{IP: sid, Port: dest.Port}
+	}
+	response, err := c.SendAndRead(ctx, dest, request, IsAll(
+		IsCorrectServer(sid),
 		IsMessageType(dhcpv4.MessageTypeAck, dhcpv4.MessageTypeNak)))
 	if err != nil {
 		return nil, fmt.Errorf("got an error while processing the request: %w", err)
@@ -77,3 +144,50 @@ func (c *Client) Renew(ctx context.Context, lease *Lease, modifiers ...dhcpv4.Mo
 		CreationTime: time.Now(),
 	}, nil
 }
+
+// Rebind broadcasts a DHCPv4 request to extend the given lease with any server, as in the
+// REBINDING state of RFC 2131, Section 4.3.2. Clients rebind when the server that granted the
+// lease did not answer renewals before T2 expired. The request does not carry a server
+// identifier, and an answer from any server is accepted.
+//
+// Like with Renew, the returned lease keeps the initial offer of the lease and the ACK of the
+// latest rebinding, and a rejection is returned as an *ErrNak. Since the server identifier of a
+// lease is taken from its latest ACK, later renewals of the returned lease go to the server that
+// answered.
+//
+// Callers keeping a lease alive typically call Renew once the renewal time (T1) has passed, and
+// keep retrying it until the rebinding time (T2). After T2 they call Rebind instead, until the
+// lease expires; at that point the address must no longer be used and a new lease has to be
+// requested.
+func (c *Client) Rebind(ctx context.Context, lease *Lease, modifiers ...dhcpv4.Modifier) (*Lease, error) {
+	if lease == nil {
+		return nil, fmt.Errorf("lease is nil")
+	}
+	if lease.declined {
+		return nil, ErrLeaseDeclined
+	}
+
+	request, err := dhcpv4.NewRenewFromAck(lease.ACK, dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithOption(dhcpv4.OptMaxMessageSize(MaxMessageSize)))...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a request: %w", err)
+	}
+
+	response, err := c.SendAndRead(ctx, c.serverAddr, request,
+		IsMessageType(dhcpv4.MessageTypeAck, dhcpv4.MessageTypeNak))
+	if err != nil {
+		return nil, fmt.Errorf("got an error while processing the request: %w", err)
+	}
+	if response.MessageType() == dhcpv4.MessageTypeNak {
+		return nil, &ErrNak{
+			Offer: lease.Offer,
+			Nak:   response,
+		}
+	}
+
+	return &Lease{
+		Offer:        lease.Offer,
+		ACK:          response,
+		CreationTime: time.Now(),
+	}, nil
+}
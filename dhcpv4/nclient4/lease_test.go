@@ -4,6 +4,7 @@ package nclient4
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -308,3 +309,287 @@ func TestLease(t *testing.T) {
 	}
 	sll.runTest(t)
 }
+
+// recordingConn records the destination of every packet written to it.
+type recordingConn struct {
+	net.PacketConn
+
+	mu    sync.Mutex
+	dests []net.Addr
+}
+
+func (r *recordingConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	r.mu.Lock()
+	r.dests = append(r.dests, addr)
+	r.mu.Unlock()
+	return r.PacketConn.WriteTo(b, addr)
+}
+
+// serveRenewal runs a server with identifier sid that answers every REQUEST
+// with a reply of type mt, and returns a client talking to it over conn.
+func serveRenewal(t *testing.T, mt dhcpv4.MessageType, sid net.IP) (*Client, *recordingConn) {
+	clientRawConn, serverRawConn, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &recordingConn{PacketConn: NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort})}
+	serverConn := NewBroadcastUDPConn(serverRawConn, &net.UDPAddr{Port: ServerPort})
+
+	handler := func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		reply, err := dhcpv4.NewReplyFromRequest(m,
+			dhcpv4.WithMessageType(mt),
+			dhcpv4.WithServerIP(sid),
+			dhcpv4.WithOption(dhcpv4.OptServerIdentifier(sid)),
+			dhcpv4.WithYourIP(m.ClientIPAddr),
+		)
+		if err != nil {
+			t.Errorf("NewReplyFromRequest = %v", err)
+			return
+		}
+		_, _ = conn.WriteTo(reply.ToBytes(), peer)
+	}
+	s, err := server4.NewServer("", nil, handler, server4.WithConn(serverConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve()
+	}()
+	t.Cleanup(func() { s.Close() })
+
+	clnt, err := NewWithConn(conn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}, WithRetry(1), WithTimeout(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { clnt.Close() })
+	return clnt, conn
+}
+
+func newTestLease(t *testing.T) *Lease {
+	offer, err := dhcpv4.New(
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeOffer),
+		dhcpv4.WithHwAddr(net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}),
+		dhcpv4.WithYourIP(net.IP{192, 168, 0, 10}),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.IP{1, 2, 3, 4})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ack, err := dhcpv4.New(
+		dhcpv4.WithReply(offer),
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeAck),
+		dhcpv4.WithYourIP(net.IP{192, 168, 0, 10}),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.IP{1, 2, 3, 4})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Lease{Offer: offer, ACK: ack, CreationTime: time.Now()}
+}
+
+func TestRenewUnicast(t *testing.T) {
+	clnt, conn := serveRenewal(t, dhcpv4.MessageTypeAck, net.IP{1, 2, 3, 4})
+	lease := newTestLease(t)
+
+	renewed, err := clnt.Renew(context.Background(), lease)
+	if err != nil {
+		t.Fatalf("Renew = %v", err)
+	}
+	if !renewed.ACK.YourIPAddr.Equal(net.IP{192, 168, 0, 10}) {
+		t.Errorf("renewed address = %v, want 192.168.0.10", renewed.ACK.YourIPAddr)
+	}
+	if renewed.ACK == lease.ACK || renewed.Offer != lease.Offer {
+		t.Errorf("Renew must keep the offer and replace the ACK")
+	}
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	want := &net.UDPAddr{IP: net.IP{1, 2, 3, 4}, Port: ServerPort}
+	if len(conn.dests) != 1 || conn.dests[0].String() != want.String() {
+		t.Errorf("renewal sent to %v, want %v", conn.dests, want)
+	}
+}
+
+func TestRenewNak(t *testing.T) {
+	clnt, _ := serveRenewal(t, dhcpv4.MessageTypeNak, net.IP{1, 2, 3, 4})
+
+	_, err := clnt.Renew(context.Background(), newTestLease(t))
+	var nak *ErrNak
+	if !errors.As(err, &nak) {
+		t.Fatalf("Renew = %v, want an *ErrNak", err)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	// The lease was granted by 1.2.3.4, but only 5.6.7.8 is up.
+	clnt, conn := serveRenewal(t, dhcpv4.MessageTypeAck, net.IP{5, 6, 7, 8})
+	lease := newTestLease(t)
+
+	rebound, err := clnt.Rebind(context.Background(), lease)
+	if err != nil {
+		t.Fatalf("Rebind = %v", err)
+	}
+	if sid := rebound.ACK.ServerIdentifier(); !sid.Equal(net.IP{5, 6, 7, 8}) {
+		t.Errorf("rebound server identifier = %v, want 5.6.7.8", sid)
+	}
+	if !rebound.ACK.YourIPAddr.Equal(net.IP{192, 168, 0, 10}) {
+		t.Errorf("rebound address = %v, want 192.168.0.10", rebound.ACK.YourIPAddr)
+	}
+
+	conn.mu.Lock()
+	if len(conn.dests) != 1 || conn.dests[0].String() != DefaultServers.String() {
+		t.Errorf("rebinding sent to %v, want %v", conn.dests, DefaultServers)
+	}
+	conn.mu.Unlock()
+
+	// Renewals now go to the server the lease was rebound with.
+	if _, err := clnt.Renew(context.Background(), rebound); err != nil {
+		t.Fatalf("Renew = %v", err)
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	want := &net.UDPAddr{IP: net.IP{5, 6, 7, 8}, Port: ServerPort}
+	if len(conn.dests) != 2 || conn.dests[1].String() != want.String() {
+		t.Errorf("renewal sent to %v, want %v", conn.dests, want)
+	}
+}
+
+func TestReleaseReusesConn(t *testing.T) {
+	clientRawConn, serverRawConn, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &recordingConn{PacketConn: NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort})}
+	serverConn := NewBroadcastUDPConn(serverRawConn, &net.UDPAddr{Port: ServerPort})
+
+	received := make(chan *dhcpv4.DHCPv4, 1)
+	s, err := server4.NewServer("", nil, func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		received <- m
+	}, server4.WithConn(serverConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve()
+	}()
+	defer s.Close()
+
+	clnt, err := NewWithConn(conn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clnt.Close()
+
+	// The leased address is not configured anywhere, and no socket is
+	// bound to it.
+	if err := clnt.Release(newTestLease(t)); err != nil {
+		t.Fatalf("Release = %v", err)
+	}
+	select {
+	case m := <-received:
+		if mt := m.MessageType(); mt != dhcpv4.MessageTypeRelease {
+			t.Errorf("message type = %v, want %v", mt, dhcpv4.MessageTypeRelease)
+		}
+		if !m.ClientIPAddr.Equal(net.IP{192, 168, 0, 10}) {
+			t.Errorf("client IP = %v, want 192.168.0.10", m.ClientIPAddr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server did not receive the release")
+	}
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	want := &net.UDPAddr{IP: net.IP{1, 2, 3, 4}, Port: ServerPort}
+	if len(conn.dests) != 1 || conn.dests[0].String() != want.String() {
+		t.Errorf("release sent to %v, want %v", conn.dests, want)
+	}
+}
+
+func TestDecline(t *testing.T) {
+	clientRawConn, serverRawConn, err := socketpair.PacketSocketPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &recordingConn{PacketConn: NewBroadcastUDPConn(clientRawConn, &net.UDPAddr{Port: ClientPort})}
+	serverConn := NewBroadcastUDPConn(serverRawConn, &net.UDPAddr{Port: ServerPort})
+
+	received := make(chan *dhcpv4.DHCPv4, 1)
+	s, err := server4.NewServer("", nil, func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		received <- m
+	}, server4.WithConn(serverConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve()
+	}()
+	defer s.Close()
+
+	clnt, err := NewWithConn(conn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clnt.Close()
+
+	lease := newTestLease(t)
+	if err := clnt.Decline(context.Background(), lease); err != nil {
+		t.Fatalf("Decline = %v", err)
+	}
+	if !lease.Declined() {
+		t.Error("lease is not marked as declined")
+	}
+	select {
+	case m := <-received:
+		if mt := m.MessageType(); mt != dhcpv4.MessageTypeDecline {
+			t.Errorf("message type = %v, want %v", mt, dhcpv4.MessageTypeDecline)
+		}
+		if ip := m.RequestedIPAddress(); !ip.Equal(net.IP{192, 168, 0, 10}) {
+			t.Errorf("requested IP = %v, want 192.168.0.10", ip)
+		}
+		if sid := m.ServerIdentifier(); !sid.Equal(net.IP{1, 2, 3, 4}) {
+			t.Errorf("server identifier = %v, want 1.2.3.4", sid)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server did not receive the decline")
+	}
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if len(conn.dests) != 1 || conn.dests[0].String() != DefaultServers.String() {
+		t.Errorf("decline sent to %v, want %v", conn.dests, DefaultServers)
+	}
+}
+
+func TestDeclinedLease(t *testing.T) {
+	clnt, conn := serveRenewal(t, dhcpv4.MessageTypeAck, net.IP{1, 2, 3, 4})
+	lease := newTestLease(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := clnt.Decline(ctx, lease); err != context.Canceled {
+		t.Fatalf("Decline with a canceled context = %v, want %v", err, context.Canceled)
+	}
+	if lease.Declined() {
+		t.Fatal("lease is declined although nothing was sent")
+	}
+
+	if err := clnt.Decline(context.Background(), lease); err != nil {
+		t.Fatalf("Decline = %v", err)
+	}
+	if _, err := clnt.Renew(context.Background(), lease); err != ErrLeaseDeclined {
+		t.Errorf("Renew = %v, want %v", err, ErrLeaseDeclined)
+	}
+	if _, err := clnt.Rebind(context.Background(), lease); err != ErrLeaseDeclined {
+		t.Errorf("Rebind = %v, want %v", err, ErrLeaseDeclined)
+	}
+	if err := clnt.Release(lease); err != ErrLeaseDeclined {
+		t.Errorf("Release = %v, want %v", err, ErrLeaseDeclined)
+	}
+
+	// Only the decline was sent.
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if len(conn.dests) != 1 {
+		t.Errorf("sent %d messages, want 1", len(conn.dests))
+	}
+}
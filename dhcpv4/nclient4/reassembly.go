@@ -0,0 +1,169 @@
+// Copyright 2018 the u-root Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nclient4
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+const (
+	// fragmentTimeout is how long fragments of an incomplete datagram are
+	// kept before they are discarded.
+	fragmentTimeout = 30 * time.Second
+
+	// maxPendingDatagrams bounds the number of datagrams being reassembled
+	// at the same time.
+	maxPendingDatagrams = 16
+
+	// maxFragmentsPerDatagram bounds the number of fragments kept for one
+	// datagram. DHCP messages span a handful of fragments at most.
+	maxFragmentsPerDatagram = 64
+
+	// maxDatagramPayload is the largest payload an IPv4 datagram can carry.
+	maxDatagramPayload = 0xffff - ipv4MinimumSize
+)
+
+// fragmentKey identifies the fragments of one IPv4 datagram, as described
+// in RFC 791.
+type fragmentKey struct {
+	src, dst [ipv4AddressSize]byte
+	id       uint16
+	protocol uint8
+}
+
+func newFragmentKey(ipHdr ipv4) fragmentKey {
+	var key fragmentKey
+	copy(key.src[:], ipHdr.sourceAddress())
+	copy(key.dst[:], ipHdr.destinationAddress())
+	key.id = ipHdr.id()
+	key.protocol = ipHdr.protocol()
+	return key
+}
+
+type fragment struct {
+	offset int
+	data   []byte
+}
+
+// pendingDatagram holds the fragments received so far for one datagram.
+type pendingDatagram struct {
+	fragments []fragment
+	// received is the number of payload bytes received so far. Fragments
+	// never overlap, so the datagram is complete once it reaches length.
+	received int
+	// length is the payload length of the whole datagram, or -1 until the
+	// last fragment has been received.
+	length   int
+	deadline time.Time
+}
+
+// add adds f to the datagram, or reports false if f is inconsistent with the
+// fragments received so far, in which case the whole datagram must be
+// discarded. Exact duplicates of a received fragment are ignored.
+func (d *pendingDatagram) add(f fragment, last bool) bool {
+	end := f.offset + len(f.data)
+	for _, g := range d.fragments {
+		if g.offset == f.offset && bytes.Equal(g.data, f.data) {
+			return true
+		}
+		if f.offset < g.offset+len(g.data) && g.offset < end {
+			return false
+		}
+	}
+	if len(d.fragments) >= maxFragmentsPerDatagram {
+		return false
+	}
+	if last {
+		if d.length >= 0 {
+			return false
+		}
+		for _, g := range d.fragments {
+			if g.offset+len(g.data) > end {
+				return false
+			}
+		}
+		d.length = end
+	} else if d.length >= 0 && end > d.length {
+		return false
+	}
+	d.fragments = append(d.fragments, f)
+	d.received += len(f.data)
+	return true
+}
+
+// complete returns the reassembled payload once every byte of the datagram
+// has been received, or nil otherwise.
+func (d *pendingDatagram) complete() []byte {
+	if d.length < 0 || d.received < d.length {
+		return nil
+	}
+	payload := make([]byte, d.length)
+	for _, f := range d.fragments {
+		copy(payload[f.offset:], f.data)
+	}
+	return payload
+}
+
+// reassembler puts fragmented IPv4 datagrams back together.
+//
+// The zero value is ready to use.
+type reassembler struct {
+	mu      sync.Mutex
+	pending map[fragmentKey]*pendingDatagram
+}
+
+// process adds the payload of the fragment with header ipHdr to its
+// datagram. Once all fragments have arrived, process returns the payload of
+// the whole datagram; until then it returns nil.
+//
+// Datagrams that are still incomplete fragmentTimeout after their first
+// fragment arrived are discarded, as are datagrams with overlapping
+// fragments, more than maxFragmentsPerDatagram fragments or a payload larger
+// than maxDatagramPayload.
+func (r *reassembler) process(ipHdr ipv4, payload []byte, now time.Time) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, d := range r.pending {
+		if now.After(d.deadline) {
+			delete(r.pending, k)
+		}
+	}
+
+	offset := int(ipHdr.fragmentOffset())
+	last := ipHdr.flags()&ipv4FlagMoreFragments == 0
+	if offset+len(payload) > maxDatagramPayload {
+		return nil
+	}
+	// All fragments but the last one carry a multiple of 8 bytes.
+	if !last && len(payload)%8 != 0 {
+		return nil
+	}
+
+	key := newFragmentKey(ipHdr)
+	d, ok := r.pending[key]
+	if !ok {
+		if len(r.pending) >= maxPendingDatagrams {
+			return nil
+		}
+		if r.pending == nil {
+			r.pending = make(map[fragmentKey]*pendingDatagram)
+		}
+		d = &pendingDatagram{length: -1, deadline: now.Add(fragmentTimeout)}
+		r.pending[key] = d
+	}
+	if !d.add(fragment{offset: offset, data: append([]byte(nil), payload...)}, last) {
+		delete(r.pending, key)
+		return nil
+	}
+
+	datagram := d.complete()
+	if datagram != nil {
+		delete(r.pending, key)
+	}
+	return datagram
+}
@@ -0,0 +1,169 @@
+// Copyright 2018 the u-root Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nclient4
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func fragmentHeader(id uint16, offset int, more bool) ipv4 {
+	var flags uint8
+	if more {
+		flags = ipv4FlagMoreFragments
+	}
+	hdr := ipv4(make([]byte, ipv4MinimumSize))
+	hdr.encode(&ipv4Fields{
+		IHL:            ipv4MinimumSize,
+		ID:             id,
+		Flags:          flags,
+		FragmentOffset: uint16(offset),
+		Protocol:       uint8(udpProtocolNumber),
+		SrcAddr:        net.IP{192, 168, 0, 1},
+		DstAddr:        net.IPv4bcast.To4(),
+	})
+	return hdr
+}
+
+func TestReassembler(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	if got := r.process(fragmentHeader(1, 0, true), []byte("01234567"), now); got != nil {
+		t.Fatalf("process(first fragment) = %q, want nil", got)
+	}
+	// A fragment of another datagram does not complete the first one.
+	if got := r.process(fragmentHeader(2, 8, false), []byte("xyz"), now); got != nil {
+		t.Fatalf("process(other datagram) = %q, want nil", got)
+	}
+	got := r.process(fragmentHeader(1, 8, false), []byte("89"), now)
+	if want := []byte("0123456789"); !bytes.Equal(got, want) {
+		t.Errorf("process(last fragment) = %q, want %q", got, want)
+	}
+}
+
+func TestReassemblerTimeout(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	r.process(fragmentHeader(1, 0, true), []byte("01234567"), now)
+	later := now.Add(fragmentTimeout + time.Second)
+	if got := r.process(fragmentHeader(1, 8, false), []byte("89"), later); got != nil {
+		t.Errorf("process(expired datagram) = %q, want nil", got)
+	}
+}
+
+func TestReassemblerInvalid(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	// Only the last fragment may carry a length that is not a multiple of 8.
+	if got := r.process(fragmentHeader(1, 0, true), []byte("0123456"), now); got != nil {
+		t.Errorf("process(misaligned fragment) = %q, want nil", got)
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("misaligned fragment was kept for reassembly")
+	}
+	if got := r.process(fragmentHeader(2, maxDatagramPayload-7, false), []byte("01234567"), now); got != nil {
+		t.Errorf("process(oversized datagram) = %q, want nil", got)
+	}
+}
+
+func TestReassemblerDuplicate(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	r.process(fragmentHeader(1, 0, true), []byte("01234567"), now)
+	r.process(fragmentHeader(1, 0, true), []byte("01234567"), now)
+	if n := len(r.pending[newFragmentKey(fragmentHeader(1, 0, true))].fragments); n != 1 {
+		t.Errorf("kept %d fragments, want 1", n)
+	}
+	got := r.process(fragmentHeader(1, 8, false), []byte("89"), now)
+	if want := []byte("0123456789"); !bytes.Equal(got, want) {
+		t.Errorf("process(last fragment) = %q, want %q", got, want)
+	}
+}
+
+func TestReassemblerOverlap(t *testing.T) {
+	type frag struct {
+		offset int
+		more   bool
+		data   string
+	}
+	for _, tt := range []struct {
+		desc  string
+		frags []frag
+	}{
+		{
+			desc:  "same offset, other data",
+			frags: []frag{{0, true, "01234567"}, {0, true, "abcdefgh"}},
+		},
+		{
+			desc:  "partial overlap",
+			frags: []frag{{0, true, "0123456789abcdef"}, {8, false, "xy"}},
+		},
+		{
+			desc:  "last fragment before received data",
+			frags: []frag{{8, true, "01234567"}, {0, false, "xy"}},
+		},
+		{
+			desc:  "second last fragment",
+			frags: []frag{{16, false, "xy"}, {8, false, "z"}},
+		},
+		{
+			desc:  "fragment past the last one",
+			frags: []frag{{8, false, "xy"}, {16, true, "01234567"}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			var r reassembler
+			now := time.Now()
+
+			for _, f := range tt.frags {
+				if got := r.process(fragmentHeader(1, f.offset, f.more), []byte(f.data), now); got != nil {
+					t.Errorf("process(%+v) = %q, want nil", f, got)
+				}
+			}
+			if len(r.pending) != 0 {
+				t.Errorf("datagram with inconsistent fragments was kept for reassembly")
+			}
+		})
+	}
+}
+
+func TestReassemblerTooManyFragments(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	for i := 0; i < maxFragmentsPerDatagram; i++ {
+		r.process(fragmentHeader(1, 8*i, true), []byte("01234567"), now)
+	}
+	if got := r.process(fragmentHeader(1, 8*maxFragmentsPerDatagram, false), []byte("89"), now); got != nil {
+		t.Errorf("process(fragment over the limit) = %q, want nil", got)
+	}
+	if len(r.pending) != 0 {
+		t.Errorf("datagram with too many fragments was kept for reassembly")
+	}
+}
+
+func TestReassemblerMaxPayload(t *testing.T) {
+	var r reassembler
+	now := time.Now()
+
+	// The last fragment ends exactly at the largest payload.
+	last := maxDatagramPayload - maxDatagramPayload%8
+	r.process(fragmentHeader(1, 0, true), []byte("01234567"), now)
+	if got := r.process(fragmentHeader(1, last, false), make([]byte, maxDatagramPayload-last+1), now); got != nil {
+		t.Errorf("process(oversized last fragment) = %q, want nil", got)
+	}
+	if got := r.process(fragmentHeader(1, last, false), make([]byte, maxDatagramPayload-last), now); got != nil {
+		t.Errorf("process(incomplete datagram) = %q, want nil", got)
+	}
+	if d := r.pending[newFragmentKey(fragmentHeader(1, 0, true))]; d == nil || d.length != maxDatagramPayload {
+		t.Errorf("datagram of %d bytes was not kept for reassembly", maxDatagramPayload)
+	}
+}
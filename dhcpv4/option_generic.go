@@ -1,7 +1,7 @@
 package dhcpv4
 
 import (
-	"fmt"
+	"github.com/insomniacslk/dhcp/internal/truncate"
 )
 
 // OptionGeneric is an option that only contains the option code and associated
@@ -18,9 +18,18 @@ func (o OptionGeneric) ToBytes() []byte {
 
 // String returns a human-readable representation of a generic option.
 func (o OptionGeneric) String() string {
-	return fmt.Sprintf("%v", o.Data)
+	return truncate.Bytes(o.Data, MaxOptionDataString)
 }
 
+// MaxOptionDataString is the number of bytes of raw data printed by the String
+// method of OptionGeneric, which is used for options without a specific type,
+// such as vendor data. Longer data is truncated and followed by its total
+// length. A value of zero or less disables truncation.
+//
+// dhcpv6.MaxOptionDataString is the same setting for DHCPv6 options, with the
+// same default.
+var MaxOptionDataString = truncate.DefaultMax
+
 // OptGeneric returns a generic option.
 func OptGeneric(code OptionCode, value []byte) Option {
 	return Option{Code: code, Value: OptionGeneric{value}}
@@ -17,3 +17,11 @@ func TestOptionGenericStringUnknown(t *testing.T) {
 	o := OptGeneric(optionCode(102), []byte{byte(MessageTypeDiscover)})
 	require.Equal(t, "unknown (102): [1]", o.String())
 }
+
+func TestOptionGenericStringTruncated(t *testing.T) {
+	defer func(max int) { MaxOptionDataString = max }(MaxOptionDataString)
+	MaxOptionDataString = 4
+
+	o := OptGeneric(OptionVendorSpecificInformation, []byte{1, 2, 3, 4, 5})
+	require.Equal(t, "Vendor Specific Information: [1 2 3 4]... (5 bytes)", o.String())
+}
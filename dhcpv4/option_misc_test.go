@@ -36,6 +36,19 @@ func TestOptDomainSearchToBytes(t *testing.T) {
 	require.Equal(t, opt.Value.ToBytes(), expected)
 }
 
+func TestOptDomainSearchString(t *testing.T) {
+	opt := OptDomainSearch(&rfc1035label.Labels{
+		Labels: []string{
+			"example.com",
+			"subnet.example.org",
+		},
+	})
+	require.Equal(t, "DNS Domain Search List: [example.com subnet.example.org]", opt.String())
+
+	m, _ := New(WithOption(opt))
+	require.Contains(t, m.Summary(), "[example.com subnet.example.org]")
+}
+
 func TestParseOptClientArchType(t *testing.T) {
 	m, _ := New(WithGeneric(OptionClientSystemArchitectureType, []byte{
 		0, 6, // EFI_IA32
@@ -2,6 +2,7 @@ package dhcpv4
 
 import (
 	"fmt"
+	"net"
 )
 
 // RelayOptions is like Options, but stringifies using the Relay Agent Specific
@@ -12,6 +13,12 @@ type RelayOptions struct {
 
 var relayHumanizer = OptionHumanizer{
 	ValueHumanizer: func(code OptionCode, data []byte) fmt.Stringer {
+		if code == LinkSelectionSubOption {
+			var ip IP
+			if ip.FromBytes(data) == nil {
+				return ip
+			}
+		}
 		return raiSubOptionValue{data}
 	},
 	CodeHumanizer: func(c uint8) OptionCode {
@@ -30,6 +37,55 @@ func (r *RelayOptions) FromBytes(data []byte) error {
 	return r.Options.FromBytes(data)
 }
 
+// CircuitID returns the Agent Circuit ID sub-option, or nil if it is not
+// present.
+//
+// The circuit ID sub-option is described by RFC 3046, Section 3.1.
+func (r RelayOptions) CircuitID() []byte {
+	return r.Get(AgentCircuitIDSubOption)
+}
+
+// RemoteID returns the Agent Remote ID sub-option, or nil if it is not
+// present.
+//
+// The remote ID sub-option is described by RFC 3046, Section 3.2.
+func (r RelayOptions) RemoteID() []byte {
+	return r.Get(AgentRemoteIDSubOption)
+}
+
+// LinkSelection returns the address of the Link Selection sub-option, or nil
+// if it is not present or invalid.
+//
+// The link selection sub-option is described by RFC 3527.
+func (r RelayOptions) LinkSelection() net.IP {
+	return GetIP(LinkSelectionSubOption, r.Options)
+}
+
+// SetCircuitID sets the Agent Circuit ID sub-option, replacing any previous
+// one.
+func (r *RelayOptions) SetCircuitID(id []byte) {
+	r.update(OptGeneric(AgentCircuitIDSubOption, id))
+}
+
+// SetRemoteID sets the Agent Remote ID sub-option, replacing any previous
+// one.
+func (r *RelayOptions) SetRemoteID(id []byte) {
+	r.update(OptGeneric(AgentRemoteIDSubOption, id))
+}
+
+// SetLinkSelection sets the Link Selection sub-option to the IPv4 address ip,
+// replacing any previous one.
+func (r *RelayOptions) SetLinkSelection(ip net.IP) {
+	r.update(Option{Code: LinkSelectionSubOption, Value: IP(ip)})
+}
+
+func (r *RelayOptions) update(o Option) {
+	if r.Options == nil {
+		r.Options = make(Options)
+	}
+	r.Update(o)
+}
+
 // OptRelayAgentInfo returns a new DHCP Relay Agent Info option.
 //
 // The relay agent info option is described by RFC 3046.
@@ -1,6 +1,7 @@
 package dhcpv4
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -46,3 +47,72 @@ func TestOptRelayAgentInfo(t *testing.T) {
 	require.Equal(t, OptionRelayAgentInformation, opt.Code)
 	require.Equal(t, wantString, opt.String())
 }
+
+func TestRelayOptionsGetters(t *testing.T) {
+	m, _ := New(WithGeneric(OptionRelayAgentInformation, []byte{
+		1, 5, 'l', 'i', 'n', 'u', 'x',
+		2, 4, 'b', 'o', 'o', 't',
+		5, 4, 192, 168, 1, 0,
+	}))
+
+	opt := m.RelayAgentInfo()
+	require.NotNil(t, opt)
+	require.Equal(t, []byte("linux"), opt.CircuitID())
+	require.Equal(t, []byte("boot"), opt.RemoteID())
+	require.Equal(t, net.IP{192, 168, 1, 0}, opt.LinkSelection())
+}
+
+func TestRelayOptionsGettersAbsent(t *testing.T) {
+	m, _ := New(WithGeneric(OptionRelayAgentInformation, []byte{
+		6, 3, 's', 'u', 'b',
+		5, 3, 192, 168, 1,
+	}))
+
+	opt := m.RelayAgentInfo()
+	require.NotNil(t, opt)
+	require.Nil(t, opt.CircuitID())
+	require.Nil(t, opt.RemoteID())
+	// A link selection sub-option must hold an IPv4 address.
+	require.Nil(t, opt.LinkSelection())
+
+	// Other sub-options are kept as is.
+	require.Equal(t, []byte("sub"), opt.Get(SubscriberIDSubOption))
+	require.Equal(t, []byte{192, 168, 1}, opt.Get(LinkSelectionSubOption))
+}
+
+func TestRelayOptionsStringLinkSelection(t *testing.T) {
+	opt := OptRelayAgentInfo(
+		OptGeneric(AgentCircuitIDSubOption, []byte("linux")),
+		OptGeneric(LinkSelectionSubOption, []byte{192, 168, 1, 0}),
+	)
+	wantString := "Relay Agent Information:\n\n    Agent Circuit ID Sub-option: linux ([108 105 110 117 120])\n    Link Selection Sub-option: 192.168.1.0\n"
+	require.Equal(t, wantString, opt.String())
+}
+
+func TestRelayOptionsSetters(t *testing.T) {
+	var r RelayOptions
+	r.SetCircuitID([]byte("eth0"))
+	r.SetCircuitID([]byte("linux"))
+	r.SetRemoteID([]byte("boot"))
+
+	want := OptRelayAgentInfo(
+		OptGeneric(GenericOptionCode(1), []byte("linux")),
+		OptGeneric(GenericOptionCode(2), []byte("boot")),
+	)
+	require.Equal(t, want.Value.ToBytes(), r.ToBytes())
+
+	r.SetLinkSelection(net.ParseIP("192.168.1.0"))
+	require.Equal(t, []byte{
+		1, 5, 'l', 'i', 'n', 'u', 'x',
+		2, 4, 'b', 'o', 'o', 't',
+		5, 4, 192, 168, 1, 0,
+	}, r.ToBytes())
+
+	m, err := New(WithOption(Option{Code: OptionRelayAgentInformation, Value: r}))
+	require.NoError(t, err)
+	opt := m.RelayAgentInfo()
+	require.NotNil(t, opt)
+	require.Equal(t, []byte("linux"), opt.CircuitID())
+	require.Equal(t, []byte("boot"), opt.RemoteID())
+	require.Equal(t, net.IP{192, 168, 1, 0}, opt.LinkSelection())
+}
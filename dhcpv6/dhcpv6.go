@@ -49,6 +49,9 @@ func MessageFromBytes(data []byte) (*Message, error) {
 	if buf.Error() != nil {
 		return nil, fmt.Errorf("failed to parse DHCPv6 header: %w", buf.Error())
 	}
+	if !messageType.IsValid() {
+		return nil, fmt.Errorf("unknown message type %s", messageType)
+	}
 	if err := d.Options.FromBytes(buf.Data()); err != nil {
 		return nil, err
 	}
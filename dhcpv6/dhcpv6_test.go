@@ -6,6 +6,7 @@ import (
 	"net"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
@@ -67,6 +68,34 @@ func TestNewMessage(t *testing.T) {
 	require.Empty(t, d.Options)
 }
 
+func TestDecapsulateRelay(t *testing.T) {
+	m := Message{MessageType: MessageTypeSolicit}
+	r1, err := EncapsulateRelay(&m, MessageTypeRelayForward, net.IPv6linklocalallnodes, net.IPv6interfacelocalallnodes)
+	require.NoError(t, err)
+	r2, err := EncapsulateRelay(r1, MessageTypeRelayForward, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+
+	// peels exactly one layer
+	inner, err := DecapsulateRelay(r2)
+	require.NoError(t, err)
+	require.Equal(t, r1, inner)
+
+	inner, err = DecapsulateRelay(inner)
+	require.NoError(t, err)
+	require.Equal(t, &m, inner)
+
+	// a non-relay message is returned as-is
+	inner, err = DecapsulateRelay(&m)
+	require.NoError(t, err)
+	require.Equal(t, &m, inner)
+
+	// a relay message without a relay-msg option is malformed
+	_, err = DecapsulateRelay(&RelayMessage{MessageType: MessageTypeRelayReply})
+	require.Error(t, err)
+	_, err = DecapsulateRelayIndex(&RelayMessage{MessageType: MessageTypeRelayReply}, 0)
+	require.Error(t, err)
+}
+
 func TestDecapsulateRelayIndex(t *testing.T) {
 	m := Message{}
 	r1, err := EncapsulateRelay(&m, MessageTypeRelayForward, net.IPv6linklocalallnodes, net.IPv6interfacelocalallnodes)
@@ -132,7 +161,7 @@ func TestToBytes(t *testing.T) {
 func TestFromAndToBytes(t *testing.T) {
 	expected := [][]byte{
 		{01, 0xab, 0xcd, 0xef, 0x00, 0x00, 0x00, 0x00},
-		[]byte("0000\x00\x01\x00\x0e\x00\x01000000000000"),
+		[]byte("\x0b000\x00\x01\x00\x0e\x00\x01000000000000"),
 	}
 	t.Parallel()
 	for i, packet := range expected {
@@ -147,9 +176,14 @@ func TestFromAndToBytes(t *testing.T) {
 
 func TestFromBytesInvalid(t *testing.T) {
 	expected := [][]byte{
+		nil,
 		{},
 		{30},
 		{12},
+		{1, 0xaa},
+		// Unknown message types.
+		{0, 0xaa, 0xbb, 0xcc},
+		[]byte("0000\x00\x01\x00\x0e\x00\x01000000000000"),
 	}
 	t.Parallel()
 	for i, packet := range expected {
@@ -160,6 +194,31 @@ func TestFromBytesInvalid(t *testing.T) {
 	}
 }
 
+func TestMessageTypeIsValid(t *testing.T) {
+	require.True(t, MessageTypeSolicit.IsValid())
+	require.True(t, MessageTypeRelayReply.IsValid())
+	require.True(t, MessageTypeDHCPv4Response.IsValid())
+	require.False(t, MessageTypeNone.IsValid())
+	require.False(t, MessageType(18).IsValid())
+	require.False(t, MessageType(255).IsValid())
+
+	types := KnownMessageTypes()
+	require.Equal(t, 19, len(types))
+	require.Equal(t, MessageTypeSolicit, types[0])
+	require.Equal(t, MessageTypeDHCPv4Response, types[len(types)-1])
+	for i, m := range types {
+		require.True(t, m.IsValid())
+		if i > 0 {
+			require.True(t, types[i-1] < m)
+		}
+	}
+}
+
+func TestMessageFromBytesUnknownType(t *testing.T) {
+	_, err := MessageFromBytes([]byte{30, 0xaa, 0xbb, 0xcc})
+	require.EqualError(t, err, "unknown message type unknown (30)")
+}
+
 func TestNewAdvertiseFromSolicit(t *testing.T) {
 	s := Message{
 		MessageType:   MessageTypeSolicit,
@@ -173,6 +232,29 @@ func TestNewAdvertiseFromSolicit(t *testing.T) {
 	require.Equal(t, a.Type(), MessageTypeAdvertise)
 }
 
+func TestNewRequestFromAdvertise(t *testing.T) {
+	cid := &DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}}
+	sid := &DUIDEN{EnterpriseNumber: 9, EnterpriseIdentifier: []byte{0x0c, 0xc0}}
+
+	adv, err := NewMessage(
+		WithClientID(cid),
+		WithServerID(sid),
+		WithIAID([4]byte{1, 2, 3, 4}),
+	)
+	require.NoError(t, err)
+	adv.MessageType = MessageTypeAdvertise
+
+	req, err := NewRequestFromAdvertise(adv)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRequest, req.Type())
+	require.Equal(t, cid, req.Options.ClientID())
+	require.Equal(t, sid, req.Options.ServerID())
+
+	adv.Options.Del(OptionServerID)
+	_, err = NewRequestFromAdvertise(adv)
+	require.Error(t, err, "a REQUEST needs the server ID of the ADVERTISE")
+}
+
 func TestNewReplyFromMessage(t *testing.T) {
 	msg := Message{
 		TransactionID: TransactionID{0xa, 0xb, 0xc},
@@ -222,6 +304,30 @@ func TestNewReplyFromMessage(t *testing.T) {
 	msg.Options.Del(OptionRapidCommit)
 }
 
+func TestNewReplyFromMessageClientID(t *testing.T) {
+	var duid DUIDLLT
+	msg := Message{
+		TransactionID: TransactionID{0xa, 0xb, 0xc},
+		MessageType:   MessageTypeInformationRequest,
+	}
+
+	// The client ID is optional in INFORMATION-REQUEST only.
+	rep, err := NewReplyFromMessage(&msg, WithServerID(&duid))
+	require.NoError(t, err)
+	require.Nil(t, rep.Options.ClientID())
+	require.NoError(t, rep.Validate())
+
+	msg.MessageType = MessageTypeRequest
+	_, err = NewReplyFromMessage(&msg, WithServerID(&duid))
+	require.Error(t, err)
+
+	msg.AddOption(OptClientID(&duid))
+	rep, err = NewReplyFromMessage(&msg, WithServerID(&duid))
+	require.NoError(t, err)
+	require.Equal(t, &duid, rep.Options.ClientID())
+	require.Equal(t, &duid, rep.Options.ServerID())
+}
+
 func TestNewMessageTypeSolicit(t *testing.T) {
 	hwAddr, err := net.ParseMAC("24:0A:9E:9F:EB:2B")
 	require.NoError(t, err)
@@ -253,6 +359,43 @@ func TestNewMessageTypeSolicit(t *testing.T) {
 	require.Equal(t, iaid, iana.IaId)
 }
 
+func TestNewMessageTypeSolicitWithIAID(t *testing.T) {
+	hwAddr, err := net.ParseMAC("24:0A:9E:9F:EB:2B")
+	require.NoError(t, err)
+
+	iaid := [4]byte{0xde, 0xad, 0xbe, 0xef}
+	s, err := NewSolicit(hwAddr, WithIAID(iaid))
+	require.NoError(t, err)
+
+	ianas := s.Options.IANA()
+	require.Equal(t, 1, len(ianas))
+	require.Equal(t, iaid, ianas[0].IaId)
+	// let the server choose T1 and T2
+	require.Equal(t, time.Duration(0), ianas[0].T1)
+	require.Equal(t, time.Duration(0), ianas[0].T2)
+}
+
+func TestNewMessageTypeSolicitDUIDUUID(t *testing.T) {
+	hwAddr, err := net.ParseMAC("24:0A:9E:9F:EB:2B")
+	require.NoError(t, err)
+
+	duid := &DUIDUUID{
+		UUID: [16]byte{
+			0xb7, 0xfd, 0x0a, 0x8c, 0x1b, 0x14, 0x10, 0xaa,
+			0xeb, 0x0a, 0x5b, 0x3f, 0xe8, 0x9d, 0x0f, 0x56,
+		},
+	}
+	s, err := NewSolicit(hwAddr, WithClientID(duid))
+	require.NoError(t, err)
+
+	// DUID-UUID is always 2 bytes of type plus 16 bytes of UUID
+	require.Equal(t, 18, len(duid.ToBytes()))
+
+	m, err := MessageFromBytes(s.ToBytes())
+	require.NoError(t, err)
+	require.True(t, duid.Equal(m.Options.ClientID()))
+}
+
 func TestGetTransactionIDMessage(t *testing.T) {
 	message, err := NewMessage()
 	require.NoError(t, err)
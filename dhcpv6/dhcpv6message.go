@@ -131,6 +131,60 @@ func (mo MessageOptions) Status() *OptStatusCode {
 	return sc
 }
 
+// Auth returns the Authentication option as defined by RFC 8415 Section
+// 21.11, or nil if no option is present.
+func (mo MessageOptions) Auth() *OptAuth {
+	if opt, ok := mo.Options.GetOne(OptionAuth).(*OptAuth); ok {
+		return opt
+	}
+	return nil
+}
+
+// RapidCommit returns whether the Rapid Commit option is present.
+func (mo MessageOptions) RapidCommit() bool {
+	return mo.Options.GetOne(OptionRapidCommit) != nil
+}
+
+// Preference returns the server preference as defined by RFC 8415 Section
+// 21.8.
+//
+// Preference returns 0 if no option is present, as clients must treat an
+// ADVERTISE without a Preference option as having a preference of 0.
+func (mo MessageOptions) Preference() uint8 {
+	if opt, ok := mo.Options.GetOne(OptionPreference).(*optPreference); ok {
+		return opt.Preference
+	}
+	return 0
+}
+
+// ReconfigureMessage returns the message type a RECONFIGURE asks the client
+// to respond with, as defined by RFC 8415 Section 21.19.
+//
+// ReconfigureMessage returns 0 if no option is present.
+func (mo MessageOptions) ReconfigureMessage() MessageType {
+	if opt, ok := mo.Options.GetOne(OptionReconfMessage).(*optReconfigureMessage); ok {
+		return opt.MessageType
+	}
+	return 0
+}
+
+// ReconfigureAccept returns whether the Reconfigure Accept option is present,
+// as defined by RFC 8415 Section 21.20.
+func (mo MessageOptions) ReconfigureAccept() bool {
+	return mo.Options.GetOne(OptionReconfAccept) != nil
+}
+
+// ServerUnicast returns the address a server allows clients to unicast to,
+// as defined by RFC 8415 Section 21.12.
+//
+// ServerUnicast returns nil if no option is present.
+func (mo MessageOptions) ServerUnicast() net.IP {
+	if opt, ok := mo.Options.GetOne(OptionUnicast).(*optServerUnicast); ok {
+		return opt.ServerAddress
+	}
+	return nil
+}
+
 // RequestedOptions returns the Options Requested Option.
 func (mo MessageOptions) RequestedOptions() OptionCodes {
 	// Technically, RFC 8415 states that ORO may only appear once in the
@@ -289,20 +343,52 @@ func (mo MessageOptions) ElapsedTime() time.Duration {
 }
 
 // InformationRefreshTime returns the Information Refresh Time option
-// as defined by RFC 815 Section 21.23.
+// as defined by RFC 8415 Section 21.23.
 //
-// InformationRefreshTime returns the provided default if no option is present.
+// InformationRefreshTime returns the provided default, usually IRTDefault, if
+// no option is present. Values smaller than IRTMinimum are raised to
+// IRTMinimum, as clients must do.
 func (mo MessageOptions) InformationRefreshTime(def time.Duration) time.Duration {
 	opt := mo.Options.GetOne(OptionInformationRefreshTime)
 	if opt == nil {
 		return def
 	}
 	if t, ok := opt.(*optInformationRefreshTime); ok {
+		if t.InformationRefreshtime < IRTMinimum {
+			return IRTMinimum
+		}
 		return t.InformationRefreshtime
 	}
 	return def
 }
 
+// SolMaxRT returns the SOL_MAX_RT option as defined by RFC 8415 Section
+// 21.24.
+//
+// SolMaxRT returns the provided default if no option is present, or if its
+// value is outside of [MaxRTMinimum, MaxRTMaximum], as clients must ignore
+// such values.
+func (mo MessageOptions) SolMaxRT(def time.Duration) time.Duration {
+	return mo.maxRT(OptionSolMaxRT, def)
+}
+
+// InfMaxRT returns the INF_MAX_RT option as defined by RFC 8415 Section
+// 21.25.
+//
+// InfMaxRT returns the provided default if no option is present, or if its
+// value is outside of [MaxRTMinimum, MaxRTMaximum], as clients must ignore
+// such values.
+func (mo MessageOptions) InfMaxRT(def time.Duration) time.Duration {
+	return mo.maxRT(OptionInfMaxRT, def)
+}
+
+func (mo MessageOptions) maxRT(code OptionCode, def time.Duration) time.Duration {
+	if opt, ok := mo.Options.GetOne(code).(*optMaxRT); ok && opt.isValid() {
+		return opt.MaxRT
+	}
+	return def
+}
+
 // FQDN returns the FQDN option as defined by RFC 4704.
 func (mo MessageOptions) FQDN() *OptFQDN {
 	opt := mo.Options.GetOne(OptionFQDN)
@@ -376,11 +462,21 @@ func GenerateTransactionID() (TransactionID, error) {
 	return tid, nil
 }
 
+// timeNow is the time source used by GetTime. Tests can override it to get
+// reproducible DUID-LLTs.
+var timeNow = time.Now
+
 // GetTime returns a time integer suitable for DUID-LLT, i.e. the current time counted
 // in seconds since January 1st, 2000, midnight UTC, modulo 2^32
 func GetTime() uint32 {
-	now := time.Since(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
-	return uint32((now.Nanoseconds() / 1000000000) % 0xffffffff)
+	return GetTimeAt(timeNow())
+}
+
+// GetTimeAt is like GetTime, but counts the seconds up to t instead of up to
+// the current time.
+func GetTimeAt(t time.Time) uint32 {
+	d := t.Sub(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	return uint32((d.Nanoseconds() / 1000000000) % 0xffffffff)
 }
 
 // NewSolicit creates a new SOLICIT message, using the given hardware address to
@@ -416,6 +512,38 @@ func NewSolicit(hwaddr net.HardwareAddr, modifiers ...Modifier) (*Message, error
 	return m, nil
 }
 
+// NewInformationRequest creates a new INFORMATION-REQUEST message, used by
+// stateless clients to ask for configuration parameters without asking for
+// addresses (RFC 8415 Section 18.2.6). The client ID is derived from the given
+// hardware address.
+//
+// Use WithRequestedOptions to ask for more options than the DNS recursive name
+// servers, the domain search list and the information refresh time.
+func NewInformationRequest(hwaddr net.HardwareAddr, modifiers ...Modifier) (*Message, error) {
+	duid := &DUIDLLT{
+		HWType:        iana.HWTypeEthernet,
+		Time:          GetTime(),
+		LinkLayerAddr: hwaddr,
+	}
+	m, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	m.MessageType = MessageTypeInformationRequest
+	m.AddOption(OptClientID(duid))
+	m.AddOption(OptRequestedOption(
+		OptionDNSRecursiveNameServer,
+		OptionDomainSearchList,
+		OptionInformationRefreshTime,
+	))
+	m.AddOption(OptElapsedTime(0))
+	// Apply modifiers
+	for _, mod := range modifiers {
+		mod(m)
+	}
+	return m, nil
+}
+
 // NewAdvertiseFromSolicit creates a new ADVERTISE packet based on an SOLICIT packet.
 func NewAdvertiseFromSolicit(sol *Message, modifiers ...Modifier) (*Message, error) {
 	if sol == nil {
@@ -525,12 +653,14 @@ func NewReplyFromMessage(msg *Message, modifiers ...Modifier) (*Message, error)
 		MessageType:   MessageTypeReply,
 		TransactionID: msg.TransactionID,
 	}
-	// add Client ID
+	// add Client ID. It is optional in INFORMATION-REQUEST only, as
+	// defined by RFC 8415 Section 18.2.6.
 	cid := msg.GetOneOption(OptionClientID)
-	if cid == nil {
+	if cid != nil {
+		rep.AddOption(cid)
+	} else if msg.Type() != MessageTypeInformationRequest {
 		return nil, errors.New("Client ID cannot be nil when building REPLY")
 	}
-	rep.AddOption(cid)
 
 	// apply modifiers
 	for _, mod := range modifiers {
@@ -621,6 +751,11 @@ func (m *Message) ToBytes() []byte {
 	return buf.Data()
 }
 
+// Length returns the length of the message once marshaled with ToBytes.
+func (m *Message) Length() int {
+	return MessageHeaderSize + m.Options.Length()
+}
+
 // GetOption returns the options associated with the code.
 func (m *Message) GetOption(code OptionCode) []Option {
 	return m.Options.Get(code)
@@ -1,7 +1,9 @@
 package dhcpv6
 
 import (
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -28,3 +30,69 @@ func TestIsOptionRequested(t *testing.T) {
 	msg2.AddOption(OptRequestedOption(OptionDNSRecursiveNameServer))
 	require.True(t, msg2.IsOptionRequested(OptionDNSRecursiveNameServer))
 }
+
+func TestGetTimeAt(t *testing.T) {
+	epoch := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, uint32(0), GetTimeAt(epoch))
+	require.Equal(t, uint32(3600), GetTimeAt(epoch.Add(time.Hour)))
+}
+
+func TestNewSolicitReproducibleTime(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time {
+		return time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC)
+	}
+
+	s, err := NewSolicit(net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	require.NoError(t, err)
+	duid, ok := s.Options.ClientID().(*DUIDLLT)
+	require.True(t, ok)
+	require.Equal(t, uint32(86400), duid.Time)
+}
+
+func TestNewInformationRequest(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}
+	m, err := NewInformationRequest(hwaddr, WithRequestedOptions(OptionNTPServer))
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeInformationRequest, m.Type())
+
+	duid, ok := m.Options.ClientID().(*DUIDLLT)
+	require.True(t, ok)
+	require.Equal(t, hwaddr, duid.LinkLayerAddr)
+	require.NotNil(t, m.GetOneOption(OptionElapsedTime))
+	require.Equal(t, OptionCodes{
+		OptionDNSRecursiveNameServer,
+		OptionDomainSearchList,
+		OptionInformationRefreshTime,
+		OptionNTPServer,
+	}, m.Options.RequestedOptions())
+	require.Nil(t, m.Options.OneIANA())
+}
+
+func TestMessageGetOption(t *testing.T) {
+	var m Message
+	require.Nil(t, m.GetOption(OptionDNSRecursiveNameServer))
+	require.Nil(t, m.GetOneOption(OptionDNSRecursiveNameServer))
+
+	dns1 := OptDNS(net.ParseIP("2001:db8::1"))
+	m.AddOption(dns1)
+	m.AddOption(OptElapsedTime(0))
+	require.Equal(t, []Option{dns1}, m.GetOption(OptionDNSRecursiveNameServer))
+	require.Equal(t, dns1, m.GetOneOption(OptionDNSRecursiveNameServer))
+
+	dns2 := OptDNS(net.ParseIP("2001:db8::2"))
+	m.AddOption(dns2)
+	require.Equal(t, []Option{dns1, dns2}, m.GetOption(OptionDNSRecursiveNameServer))
+	require.Equal(t, dns1, m.GetOneOption(OptionDNSRecursiveNameServer))
+}
+
+func TestMessageLength(t *testing.T) {
+	var m Message
+	require.Equal(t, MessageHeaderSize, m.Length())
+	require.Equal(t, len(m.ToBytes()), m.Length())
+
+	s, err := NewSolicit(net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	require.NoError(t, err)
+	s.AddOption(OptBootFileURL("http://[2001:db8::1]/boot.efi"))
+	require.Equal(t, len(s.ToBytes()), s.Length())
+}
@@ -0,0 +1,102 @@
+package dhcpv6
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists the ways in which a message violates the validation
+// rules of RFC 8415 Section 16.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid message: %s", strings.Join(e.Violations, "; "))
+}
+
+// optionRules are the options a message type must and must not carry.
+type optionRules struct {
+	required  []OptionCode
+	forbidden []OptionCode
+}
+
+// messageRules are the per-message-type rules of RFC 8415 Sections 16.2 to
+// 16.12.
+var messageRules = map[MessageType]optionRules{
+	MessageTypeSolicit: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeAdvertise: {
+		required: []OptionCode{OptionServerID, OptionClientID},
+	},
+	MessageTypeRequest: {
+		required: []OptionCode{OptionServerID, OptionClientID},
+	},
+	MessageTypeConfirm: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeRenew: {
+		required: []OptionCode{OptionServerID, OptionClientID},
+	},
+	MessageTypeRebind: {
+		required:  []OptionCode{OptionClientID},
+		forbidden: []OptionCode{OptionServerID},
+	},
+	MessageTypeDecline: {
+		required: []OptionCode{OptionServerID, OptionClientID},
+	},
+	MessageTypeRelease: {
+		required: []OptionCode{OptionServerID, OptionClientID},
+	},
+	MessageTypeReply: {
+		required: []OptionCode{OptionServerID},
+	},
+	MessageTypeReconfigure: {
+		required: []OptionCode{OptionServerID, OptionClientID, OptionReconfMessage},
+	},
+	MessageTypeInformationRequest: {
+		forbidden: []OptionCode{OptionIANA, OptionIATA, OptionIAPD},
+	},
+}
+
+// Validate checks m against the rules of RFC 8415 Section 16, which tell
+// the receiver of a message which ones to discard: the options required
+// or forbidden for each message type. Known message types without such
+// rules, like the ones of leasequery or DHCPv4-over-DHCPv6, are accepted
+// as is. Relay message types are never valid in a Message.
+//
+// Validate is not called when building, serializing or parsing messages,
+// so that malformed messages can still be built and inspected on purpose.
+// It returns a *ValidationError listing every violation, or nil.
+func (m *Message) Validate() error {
+	switch {
+	case m.MessageType == MessageTypeRelayForward || m.MessageType == MessageTypeRelayReply:
+		return &ValidationError{
+			Violations: []string{fmt.Sprintf("%s is a relay message type", m.MessageType)},
+		}
+	case !m.MessageType.IsValid():
+		return &ValidationError{
+			Violations: []string{fmt.Sprintf("unexpected message type %s", m.MessageType)},
+		}
+	}
+	rules := messageRules[m.MessageType]
+
+	var violations []string
+	for _, code := range rules.required {
+		if m.GetOneOption(code) == nil {
+			violations = append(violations, fmt.Sprintf("%s must contain option %s", m.MessageType, code))
+		}
+	}
+	for _, code := range rules.forbidden {
+		if m.GetOneOption(code) != nil {
+			violations = append(violations, fmt.Sprintf("%s must not contain option %s", m.MessageType, code))
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package dhcpv6
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageValidate(t *testing.T) {
+	cid := OptClientID(&DUIDLL{HWType: 1, LinkLayerAddr: net.HardwareAddr{1, 2, 3, 4, 5, 6}})
+	sid := OptServerID(&DUIDLL{HWType: 1, LinkLayerAddr: net.HardwareAddr{6, 5, 4, 3, 2, 1}})
+
+	for _, tt := range []struct {
+		name       string
+		msg        *Message
+		violations []string
+	}{
+		{
+			name: "valid solicit",
+			msg:  &Message{MessageType: MessageTypeSolicit, Options: MessageOptions{Options{cid}}},
+		},
+		{
+			name: "solicit with server ID and without client ID",
+			msg:  &Message{MessageType: MessageTypeSolicit, Options: MessageOptions{Options{sid}}},
+			violations: []string{
+				"SOLICIT must contain option Client ID",
+				"SOLICIT must not contain option Server ID",
+			},
+		},
+		{
+			name: "valid request",
+			msg:  &Message{MessageType: MessageTypeRequest, Options: MessageOptions{Options{cid, sid}}},
+		},
+		{
+			name:       "request without server ID",
+			msg:        &Message{MessageType: MessageTypeRequest, Options: MessageOptions{Options{cid}}},
+			violations: []string{"REQUEST must contain option Server ID"},
+		},
+		{
+			name:       "reply without server ID",
+			msg:        &Message{MessageType: MessageTypeReply},
+			violations: []string{"REPLY must contain option Server ID"},
+		},
+		{
+			name: "information request without client ID",
+			msg:  &Message{MessageType: MessageTypeInformationRequest},
+		},
+		{
+			name:       "information request with IA_NA",
+			msg:        &Message{MessageType: MessageTypeInformationRequest, Options: MessageOptions{Options{&OptIANA{}}}},
+			violations: []string{"INFORMATION-REQUEST must not contain option IANA"},
+		},
+		{
+			name:       "relay message type",
+			msg:        &Message{MessageType: MessageTypeRelayForward},
+			violations: []string{"RELAY-FORW is a relay message type"},
+		},
+		{
+			name: "leasequery without rules",
+			msg:  &Message{MessageType: MessageTypeLeaseQuery},
+		},
+		{
+			name: "DHCPv4 response without rules",
+			msg:  &Message{MessageType: MessageTypeDHCPv4Response},
+		},
+		{
+			name:       "unknown message type",
+			msg:        &Message{MessageType: MessageType(30)},
+			violations: []string{"unexpected message type unknown (30)"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			if tt.violations == nil {
+				require.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "Validate = %v", err)
+			require.Equal(t, tt.violations, verr.Violations)
+		})
+	}
+}
+
+func TestMessageValidateBuilders(t *testing.T) {
+	sol, err := NewSolicit(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	require.NoError(t, sol.Validate())
+
+	ir, err := NewInformationRequest(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	require.NoError(t, ir.Validate())
+}
@@ -109,7 +109,6 @@ func (r *RelayMessage) LongString(spaceIndent int) string {
 	indent := strings.Repeat(" ", spaceIndent)
 
 	var s strings.Builder
-	s.WriteString(indent)
 	s.WriteString("RelayMessage{\n")
 	s.WriteString(indent)
 	s.WriteString(fmt.Sprintf("  MessageType=%s\n", r.MessageType))
@@ -122,7 +121,9 @@ func (r *RelayMessage) LongString(spaceIndent int) string {
 	s.WriteString(indent)
 	s.WriteString("  Options: ")
 	s.WriteString(r.Options.Options.LongString(spaceIndent + 2))
-	s.WriteString("\n}")
+	s.WriteString("\n")
+	s.WriteString(indent)
+	s.WriteString("}")
 
 	return s.String()
 }
@@ -139,6 +140,11 @@ func (r *RelayMessage) ToBytes() []byte {
 	return buf.Data()
 }
 
+// Length returns the length of the relay message once marshaled with ToBytes.
+func (r *RelayMessage) Length() int {
+	return RelayHeaderSize + r.Options.Length()
+}
+
 // GetOption returns the options associated with the code.
 func (r *RelayMessage) GetOption(code OptionCode) []Option {
 	return r.Options.Get(code)
@@ -3,6 +3,7 @@ package dhcpv6
 import (
 	"bytes"
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -119,3 +120,102 @@ func TestNewRelayRepFromRelayForw(t *testing.T) {
 	_, err = NewRelayReplFromRelayForw(&rf, nil)
 	require.Error(t, err)
 }
+
+func TestRelayMessageGetOption(t *testing.T) {
+	var r RelayMessage
+	require.Nil(t, r.GetOption(OptionInterfaceID))
+	require.Nil(t, r.GetOneOption(OptionInterfaceID))
+
+	iid1 := OptInterfaceID([]byte("eth0"))
+	r.AddOption(iid1)
+	r.AddOption(OptRelayMessage(&Message{}))
+	require.Equal(t, []Option{iid1}, r.GetOption(OptionInterfaceID))
+	require.Equal(t, iid1, r.GetOneOption(OptionInterfaceID))
+
+	iid2 := OptInterfaceID([]byte("eth1"))
+	r.AddOption(iid2)
+	require.Equal(t, []Option{iid1, iid2}, r.GetOption(OptionInterfaceID))
+	require.Equal(t, iid1, r.GetOneOption(OptionInterfaceID))
+}
+
+func TestRelayMessageLength(t *testing.T) {
+	var r RelayMessage
+	require.Equal(t, RelayHeaderSize, r.Length())
+	require.Equal(t, len(r.ToBytes()), r.Length())
+
+	s, err := NewSolicit(net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf})
+	require.NoError(t, err)
+	r.AddOption(OptInterfaceID([]byte("eth0")))
+	r.AddOption(OptRelayMessage(s))
+	require.Equal(t, len(r.ToBytes()), r.Length())
+	require.Equal(t, RelayHeaderSize+4+4+4+s.Length(), r.Length())
+}
+
+func TestNewRelayReplEchoesInterfaceID(t *testing.T) {
+	s, err := NewMessage()
+	require.NoError(t, err)
+	s.AddOption(OptClientID(&DUIDLL{}))
+
+	// Two relays, each with its own interface-id.
+	inner, err := EncapsulateRelay(s, MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	inner.AddOption(OptInterfaceID([]byte("eth0.5")))
+	outer, err := EncapsulateRelay(inner, MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	outer.AddOption(OptInterfaceID([]byte{0x00, 0x01, 0xff}))
+
+	// Parse it, as a server would.
+	rf, err := RelayMessageFromBytes(outer.ToBytes())
+	require.NoError(t, err)
+
+	a, err := NewAdvertiseFromSolicit(s)
+	require.NoError(t, err)
+	rr, err := NewRelayReplFromRelayForw(rf, a)
+	require.NoError(t, err)
+
+	parsed, err := RelayMessageFromBytes(rr.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x01, 0xff}, parsed.Options.InterfaceID())
+	innerReply, ok := parsed.Options.RelayMessage().(*RelayMessage)
+	require.True(t, ok)
+	require.Equal(t, []byte("eth0.5"), innerReply.Options.InterfaceID())
+}
+
+func TestRelayMessageSummary(t *testing.T) {
+	s, err := NewMessage()
+	require.NoError(t, err)
+	s.AddOption(OptElapsedTime(0))
+	inner, err := EncapsulateRelay(s, MessageTypeRelayForward, net.IPv6loopback, net.IPv6linklocalallnodes)
+	require.NoError(t, err)
+	outer, err := EncapsulateRelay(inner, MessageTypeRelayForward, net.IPv6unspecified, net.IPv6loopback)
+	require.NoError(t, err)
+
+	summary := outer.Summary()
+	require.Contains(t, summary, "  HopCount=1\n")
+	require.Contains(t, summary, "  LinkAddr=::\n")
+	// The inner relay and the SOLICIT are printed, each indented further.
+	require.Contains(t, summary, "\n    Relay Message: RelayMessage{\n      MessageType=RELAY-FORW\n      HopCount=0\n")
+	require.Contains(t, summary, "\n        Relay Message: Message{\n          MessageType=SOLICIT\n")
+	require.Contains(t, summary, "\n            Elapsed Time: 0s\n")
+	require.True(t, strings.HasSuffix(summary, "\n      ]\n    }\n  ]\n}"), summary)
+}
+
+func TestRelayMessageGetInnerMessage(t *testing.T) {
+	s, err := NewMessage()
+	require.NoError(t, err)
+	inner, err := EncapsulateRelay(s, MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	outer, err := EncapsulateRelay(inner, MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+
+	m, err := outer.GetInnerMessage()
+	require.NoError(t, err)
+	require.Equal(t, s, m)
+
+	// A relay chain whose innermost relay has no Relay Message option.
+	empty := &RelayMessage{MessageType: MessageTypeRelayForward}
+	broken, err := EncapsulateRelay(empty, MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	_, err = broken.GetInnerMessage()
+	require.Error(t, err)
+}
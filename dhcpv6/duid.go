@@ -118,9 +118,10 @@ type DUIDEN struct {
 	EnterpriseIdentifier []byte
 }
 
-// String pretty-prints DUIDEN information.
+// String pretty-prints DUIDEN information. The identifier is opaque, so it is
+// printed in hex.
 func (d DUIDEN) String() string {
-	return fmt.Sprintf("DUID-EN{EnterpriseNumber=%d EnterpriseIdentifier=%s}", d.EnterpriseNumber, d.EnterpriseIdentifier)
+	return fmt.Sprintf("DUID-EN{EnterpriseNumber=%d EnterpriseIdentifier=0x%x}", d.EnterpriseNumber, d.EnterpriseIdentifier)
 }
 
 // DUIDType returns the DUID_EN type.
@@ -163,9 +164,11 @@ type DUIDUUID struct {
 	UUID [16]byte
 }
 
-// String pretty-prints DUIDUUID information.
+// String pretty-prints DUIDUUID information, with the UUID in its canonical
+// 8-4-4-4-12 form.
 func (d DUIDUUID) String() string {
-	return fmt.Sprintf("DUID-UUID{%#x}", d.UUID[:])
+	return fmt.Sprintf("DUID-UUID{%x-%x-%x-%x-%x}",
+		d.UUID[0:4], d.UUID[4:6], d.UUID[6:8], d.UUID[8:10], d.UUID[10:16])
 }
 
 // DUIDType returns the DUID_UUID type.
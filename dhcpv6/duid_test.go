@@ -78,7 +78,21 @@ func TestFromBytes(t *testing.T) {
 				EnterpriseNumber:     0x1,
 				EnterpriseIdentifier: []byte("foo"),
 			},
-			stringer: "DUID-EN{EnterpriseNumber=1 EnterpriseIdentifier=foo}",
+			stringer: "DUID-EN{EnterpriseNumber=1 EnterpriseIdentifier=0x666f6f}",
+		},
+		{
+			// Example from RFC 8415 Section 11.3.
+			name: "DUID-EN-RFC8415",
+			buf: []byte{
+				0, 2, // DUID_EN
+				0, 0, 0, 9, // EnterpriseNumber (Cisco)
+				0x0c, 0xc0, 0x84, 0xd3, 0x03, 0x00, 0x09, 0x12, // identifier
+			},
+			want: &DUIDEN{
+				EnterpriseNumber:     9,
+				EnterpriseIdentifier: []byte{0x0c, 0xc0, 0x84, 0xd3, 0x03, 0x00, 0x09, 0x12},
+			},
+			stringer: "DUID-EN{EnterpriseNumber=9 EnterpriseIdentifier=0x0cc084d303000912}",
 		},
 		{
 			name: "DUID-UUID",
@@ -97,7 +111,7 @@ func TestFromBytes(t *testing.T) {
 					0x01, 0x02, 0x03, 0x04,
 				},
 			},
-			stringer: "DUID-UUID{0x01020304010203040102030401020304}",
+			stringer: "DUID-UUID{01020304-0102-0304-0102-030401020304}",
 		},
 		{
 			name: "DUIDOpaque",
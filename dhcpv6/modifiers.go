@@ -20,7 +20,7 @@ func WithClientID(duid DUID) Modifier {
 	return WithOption(OptClientID(duid))
 }
 
-// WithServerID adds a client ID option to a DHCPv6 packet
+// WithServerID adds a server ID option to a DHCPv6 packet
 func WithServerID(duid DUID) Modifier {
 	return WithOption(OptServerID(duid))
 }
@@ -128,7 +128,17 @@ func WithDomainSearchList(searchlist ...string) Modifier {
 
 // WithRapidCommit adds the rapid commit option to a message.
 func WithRapidCommit(d DHCPv6) {
-	d.UpdateOption(&OptionGeneric{OptionCode: OptionRapidCommit})
+	d.UpdateOption(OptRapidCommit())
+}
+
+// WithPreference adds or updates the server preference option.
+func WithPreference(pref uint8) Modifier {
+	return WithOption(OptPreference(pref))
+}
+
+// WithReconfigureAccept adds the reconfigure accept option to a message.
+func WithReconfigureAccept(d DHCPv6) {
+	d.UpdateOption(OptReconfigureAccept())
 }
 
 // WithRequestedOptions adds requested options to the packet
@@ -117,6 +117,30 @@ func TestWithIAPD(t *testing.T) {
 	opt := d.Options.IAPD()
 	require.Equal(t, 1, len(opt))
 	require.Equal(t, OptionIAPD, opt[0].Code())
+	require.Equal(t, [4]byte{1, 2, 3, 4}, opt[0].IaId)
+	require.Equal(t, []*OptIAPrefix{prefix}, opt[0].Options.Prefixes())
+
+	// Lifetimes are expressed in seconds on the wire, so use a prefix
+	// with second-granularity lifetimes for the encoding check.
+	var e Message
+	WithIAPD([4]byte{1, 2, 3, 4}, &OptIAPrefix{
+		PreferredLifetime: 3600 * time.Second,
+		ValidLifetime:     7200 * time.Second,
+		Prefix:            pre,
+	})(&e)
+	want := []byte{
+		0, 25, // IAPD option code
+		0, 41, // length
+		1, 2, 3, 4, // IAID
+		0, 0, 0, 0, // T1
+		0, 0, 0, 0, // T2
+		0, 26, 0, 25, // 26 = IAPrefix Option, 25 = length
+		0, 0, 0x0e, 0x10, // IAPrefix preferredLifetime
+		0, 0, 0x1c, 0x20, // IAPrefix validLifetime
+		48,                                                               // IAPrefix prefixLength
+		0x20, 0x01, 0x0d, 0xb8, 0x76, 0x89, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // IAPrefix ipv6Prefix
+	}
+	require.Equal(t, want, e.Options.ToBytes())
 }
 
 func TestWithClientLinkLayerAddress(t *testing.T) {
@@ -37,6 +37,11 @@ var (
 	ErrNoResponse = errors.New("no matching response packet received")
 )
 
+// maxUnfragmentedSize is the largest DHCPv6 message that fits in the IPv6
+// minimum link MTU of 1280 bytes, after the IPv6 and UDP headers. It is the
+// default threshold of WithFragmentationWarning.
+const maxUnfragmentedSize = 1280 - 40 - 8
+
 // pendingCh is a channel associated with a pending TransactionID.
 type pendingCh struct {
 	// SendAndRead closes done to indicate that it wishes for no more
@@ -52,9 +57,14 @@ type Client struct {
 	ifaceHWAddr net.HardwareAddr
 	conn        net.PacketConn
 	timeout     time.Duration
+	maxTimeout  time.Duration
 	retry       int
 	logger      logger
 
+	// fragmentationWarning is the size above which sent messages are
+	// logged as possibly fragmented, or 0 to never log them.
+	fragmentationWarning int
+
 	// bufferCap is the channel capacity for each TransactionID.
 	bufferCap int
 
@@ -75,6 +85,12 @@ type Client struct {
 	// printDropped logs dropped packets to logger if true.
 	printDropped bool
 
+	maxRTMu sync.Mutex
+	// solMaxRT and infMaxRT are the last SOL_MAX_RT and INF_MAX_RT values
+	// received from a server, or 0 if none was received.
+	solMaxRT time.Duration
+	infMaxRT time.Duration
+
 	pendingMu sync.Mutex
 	// pending stores the distribution channels for each pending
 	// TransactionID. receiveLoop uses this map to determine which channel
@@ -155,6 +171,8 @@ func NewWithConn(conn net.PacketConn, ifaceHWAddr net.HardwareAddr, opts ...Clie
 		conn:        conn,
 		logger:      emptyLogger{},
 
+		fragmentationWarning: maxUnfragmentedSize,
+
 		done:    make(chan struct{}),
 		pending: make(map[dhcpv6.TransactionID]*pendingCh),
 	}
@@ -262,6 +280,22 @@ func WithTimeout(d time.Duration) ClientOpt {
 	}
 }
 
+// WithMaxTimeout configures the maximum retransmission timeout (MRT) as defined
+// by RFC 8415 Section 15. The retransmission timeout doubles after each
+// unanswered attempt, but never grows beyond the MRT.
+//
+// Default is 0, which does not limit the retransmission timeout.
+//
+// Once a server sent a SOL_MAX_RT or INF_MAX_RT option, its value replaces d
+// as the MRT of SOLICIT or INFORMATION-REQUEST messages respectively, for the
+// rest of the client's lifetime, as required by RFC 8415 Sections 18.2.9 and
+// 18.2.10. Other messages keep using d.
+func WithMaxTimeout(d time.Duration) ClientOpt {
+	return func(c *Client) {
+		c.maxTimeout = d
+	}
+}
+
 // WithLogDroppedPackets logs a short message for dropped packets.
 func WithLogDroppedPackets() ClientOpt {
 	return func(c *Client) {
@@ -269,6 +303,17 @@ func WithLogDroppedPackets() ClientOpt {
 	}
 }
 
+// WithFragmentationWarning configures the size in bytes above which sent
+// messages are logged as they may be fragmented. 0 disables the warning.
+//
+// Default is 1232 bytes, the largest message that fits in the IPv6 minimum
+// link MTU.
+func WithFragmentationWarning(size int) ClientOpt {
+	return func(c *Client) {
+		c.fragmentationWarning = size
+	}
+}
+
 // WithRetry configures the number of retransmissions to attempt.
 //
 // Default is 3.
@@ -343,14 +388,35 @@ func (c *Client) InterfaceAddr() net.HardwareAddr {
 	return b
 }
 
+// isRapidCommitReply matches a REPLY to a rapid-commit SOLICIT, or an ADVERTISE
+// from a server ignoring the rapid-commit option.
+//
+// RFC 8415 Section 18.2.1: a client MUST discard any Reply messages that do not
+// include a Rapid Commit option in response to its rapid-commit Solicit.
+func isRapidCommitReply(p *dhcpv6.Message) bool {
+	switch p.MessageType {
+	case dhcpv6.MessageTypeReply:
+		return p.Options.RapidCommit()
+	case dhcpv6.MessageTypeAdvertise:
+		return true
+	default:
+		return false
+	}
+}
+
 // RapidSolicit sends a solicitation message with the RapidCommit option and
 // returns the first valid reply received.
+//
+// Servers are free to ignore the RapidCommit option. If the first matching
+// response is an ADVERTISE instead of a REPLY, RapidSolicit falls back to the
+// regular four-message exchange and returns the REPLY to the REQUEST built from
+// that ADVERTISE.
 func (c *Client) RapidSolicit(ctx context.Context, modifiers ...dhcpv6.Modifier) (*dhcpv6.Message, error) {
 	solicit, err := dhcpv6.NewSolicit(c.ifaceHWAddr, append(modifiers, dhcpv6.WithRapidCommit)...)
 	if err != nil {
 		return nil, err
 	}
-	msg, err := c.SendAndRead(ctx, c.serverAddr, solicit, IsMessageType(dhcpv6.MessageTypeReply, dhcpv6.MessageTypeAdvertise))
+	msg, err := c.SendAndRead(ctx, c.serverAddr, solicit, isRapidCommitReply)
 	if err != nil {
 		return nil, err
 	}
@@ -392,6 +458,22 @@ func (c *Client) Request(ctx context.Context, advertise *dhcpv6.Message, modifie
 	return c.SendAndRead(ctx, c.serverAddr, request, nil)
 }
 
+// InformationRequest sends an information request message, asking for
+// configuration parameters but no addresses, and returns the first REPLY
+// received.
+//
+// The client does not refresh the configuration by itself. As RFC 8415
+// Section 18.2.6 requires, callers should send the next information request
+// after reply.Options.InformationRefreshTime(dhcpv6.IRTDefault), which also
+// applies the IRT_MINIMUM lower bound.
+func (c *Client) InformationRequest(ctx context.Context, modifiers ...dhcpv6.Modifier) (*dhcpv6.Message, error) {
+	ir, err := dhcpv6.NewInformationRequest(c.ifaceHWAddr, modifiers...)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendAndRead(ctx, c.serverAddr, ir, IsMessageType(dhcpv6.MessageTypeReply))
+}
+
 // send sends p to destination and returns a response channel.
 //
 // The returned function must be called after all desired responses have been
@@ -427,7 +509,11 @@ func (c *Client) send(dest net.Addr, msg *dhcpv6.Message) (<-chan *dhcpv6.Messag
 		c.pendingMu.Unlock()
 	}
 
-	if _, err := c.conn.WriteTo(msg.ToBytes(), dest); err != nil {
+	packet := msg.ToBytes()
+	if c.fragmentationWarning > 0 && len(packet) > c.fragmentationWarning {
+		c.logger.Printf("Message of %d bytes is larger than %d bytes and may be fragmented", len(packet), c.fragmentationWarning)
+	}
+	if _, err := c.conn.WriteTo(packet, dest); err != nil {
 		cancel()
 		return nil, nil, fmt.Errorf("error writing packet to connection: %v", err)
 	}
@@ -440,10 +526,19 @@ var errDeadlineExceeded = errors.New("INTERNAL ERROR: deadline exceeded")
 // SendAndRead sends a packet p to a destination dest and waits for the first
 // response matching `match` as well as its Transaction ID.
 //
+// If msg contains an Elapsed Time option, it is updated with the time elapsed
+// since the first transmission every time msg is retransmitted.
+//
 // If match is nil, the first packet matching the Transaction ID is returned.
 func (c *Client) SendAndRead(ctx context.Context, dest *net.UDPAddr, msg *dhcpv6.Message, match Matcher) (*dhcpv6.Message, error) {
 	var response *dhcpv6.Message
-	err := c.retryFn(func(timeout time.Duration) error {
+	start := time.Now()
+	err := c.retryFn(c.maxTimeoutFor(msg.MessageType), func(timeout time.Duration) error {
+		// RFC 8415 Section 21.9: the Elapsed Time option is updated in
+		// every retransmission of the message.
+		if msg.GetOneOption(dhcpv6.OptionElapsedTime) != nil {
+			msg.UpdateOption(dhcpv6.OptElapsedTime(time.Since(start)))
+		}
 		ch, rem, err := c.send(dest, msg)
 		if err != nil {
 			return err
@@ -463,6 +558,10 @@ func (c *Client) SendAndRead(ctx context.Context, dest *net.UDPAddr, msg *dhcpv6
 				return ctx.Err()
 
 			case packet := <-ch:
+				// RFC 8415 Section 18.2.9: SOL_MAX_RT and INF_MAX_RT
+				// are processed even if the message is otherwise
+				// discarded.
+				c.updateMaxRT(packet)
 				if match == nil || match(packet) {
 					c.logger.PrintMessage("received message", packet)
 					response = packet
@@ -480,7 +579,31 @@ func (c *Client) SendAndRead(ctx context.Context, dest *net.UDPAddr, msg *dhcpv6
 	return response, nil
 }
 
-func (c *Client) retryFn(fn func(timeout time.Duration) error) error {
+// maxTimeoutFor returns the maximum retransmission timeout of messages of type
+// t: the last value received from a server for SOLICIT and
+// INFORMATION-REQUEST messages, or the one configured by WithMaxTimeout.
+func (c *Client) maxTimeoutFor(t dhcpv6.MessageType) time.Duration {
+	c.maxRTMu.Lock()
+	defer c.maxRTMu.Unlock()
+	switch {
+	case t == dhcpv6.MessageTypeSolicit && c.solMaxRT > 0:
+		return c.solMaxRT
+	case t == dhcpv6.MessageTypeInformationRequest && c.infMaxRT > 0:
+		return c.infMaxRT
+	}
+	return c.maxTimeout
+}
+
+// updateMaxRT records the SOL_MAX_RT and INF_MAX_RT values of msg. Values
+// outside of the range allowed by RFC 8415 are ignored.
+func (c *Client) updateMaxRT(msg *dhcpv6.Message) {
+	c.maxRTMu.Lock()
+	defer c.maxRTMu.Unlock()
+	c.solMaxRT = msg.Options.SolMaxRT(c.solMaxRT)
+	c.infMaxRT = msg.Options.InfMaxRT(c.infMaxRT)
+}
+
+func (c *Client) retryFn(maxTimeout time.Duration, fn func(timeout time.Duration) error) error {
 	timeout := c.timeout
 
 	// Each retry takes the amount of timeout at worst.
@@ -493,6 +616,9 @@ func (c *Client) retryFn(fn func(timeout time.Duration) error) error {
 		case errDeadlineExceeded:
 			// Double timeout, then retry.
 			timeout *= 2
+			if maxTimeout > 0 && timeout > maxTimeout {
+				timeout = maxTimeout
+			}
 
 		default:
 			return err
@@ -270,3 +270,216 @@ func TestMultipleSendAndReadOne(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryMaxTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		maxTimeout time.Duration
+		want       []time.Duration
+	}{
+		{
+			desc: "unlimited",
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			desc:       "capped",
+			maxTimeout: 3 * time.Second,
+			want:       []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &Client{timeout: time.Second, retry: len(tt.want)}
+			WithMaxTimeout(tt.maxTimeout)(c)
+
+			var got []time.Duration
+			err := c.retryFn(c.maxTimeout, func(timeout time.Duration) error {
+				got = append(got, timeout)
+				return errDeadlineExceeded
+			})
+			require.Equal(t, errDeadlineExceeded, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServerMaxRT(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	xid := dhcpv6.TransactionID{0x44, 0x44, 0x44}
+	adv := newPacket(xid)
+	adv.MessageType = dhcpv6.MessageTypeAdvertise
+	adv.AddOption(dhcpv6.OptSolMaxRT(2 * time.Minute))
+	// Out of range, ignored.
+	adv.AddOption(dhcpv6.OptInfMaxRT(30 * time.Second))
+
+	mc, _ := serveAndClient(ctx, [][]*dhcpv6.Message{{adv}},
+		WithTimeout(100*time.Millisecond), WithMaxTimeout(time.Hour))
+	defer mc.Close()
+
+	// A non-matching response still carries the server's values.
+	msg := newPacket(xid)
+	msg.MessageType = dhcpv6.MessageTypeSolicit
+	_, err := mc.SendAndRead(ctx, AllDHCPServers, msg, IsMessageType(dhcpv6.MessageTypeReply))
+	require.Equal(t, ErrNoResponse, err)
+
+	require.Equal(t, 2*time.Minute, mc.maxTimeoutFor(dhcpv6.MessageTypeSolicit))
+	require.Equal(t, time.Hour, mc.maxTimeoutFor(dhcpv6.MessageTypeInformationRequest))
+	require.Equal(t, time.Hour, mc.maxTimeoutFor(dhcpv6.MessageTypeRequest))
+}
+
+func TestIsRapidCommitReply(t *testing.T) {
+	reply := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeReply}
+	require.False(t, isRapidCommitReply(reply), "REPLY without rapid commit")
+
+	dhcpv6.WithRapidCommit(reply)
+	require.True(t, isRapidCommitReply(reply), "REPLY with rapid commit")
+
+	adv := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeAdvertise}
+	require.True(t, isRapidCommitReply(adv), "ADVERTISE")
+
+	req := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeRequest}
+	require.False(t, isRapidCommitReply(req), "REQUEST")
+}
+
+// writeConn sends every packet written to it on written.
+type writeConn struct {
+	net.PacketConn
+	written chan []byte
+}
+
+func (c *writeConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	c.written <- append([]byte(nil), b...)
+	return c.PacketConn.WriteTo(b, addr)
+}
+
+func TestSendAndReadUpdatesElapsedTime(t *testing.T) {
+	clientRawConn, _, err := socketpair.PacketSocketPair()
+	require.NoError(t, err)
+	conn := &writeConn{PacketConn: clientRawConn, written: make(chan []byte, 2)}
+
+	// Nobody answers, so the client retransmits.
+	mc, err := NewWithConn(conn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf},
+		WithRetry(2), WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer mc.Close()
+
+	msg := newPacket([3]byte{0x33, 0x33, 0x33})
+	msg.AddOption(dhcpv6.OptElapsedTime(0))
+	_, err = mc.SendAndRead(context.Background(), AllDHCPServers, msg, nil)
+	require.Equal(t, ErrNoResponse, err)
+
+	var sent []*dhcpv6.Message
+	for i := 0; i < 2; i++ {
+		m, err := dhcpv6.MessageFromBytes(<-conn.written)
+		require.NoError(t, err)
+		sent = append(sent, m)
+	}
+	require.Equal(t, time.Duration(0), sent[0].Options.ElapsedTime())
+	require.True(t, sent[1].Options.ElapsedTime() >= 50*time.Millisecond)
+}
+
+func TestInformationRequest(t *testing.T) {
+	clientRawConn, serverRawConn, err := socketpair.PacketSocketPair()
+	require.NoError(t, err)
+
+	mc, err := NewWithConn(clientRawConn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf},
+		WithRetry(1), WithTimeout(2*time.Second))
+	require.NoError(t, err)
+	defer mc.Close()
+
+	dns := net.ParseIP("2001:db8::53")
+	var received *dhcpv6.Message
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+		received = m.(*dhcpv6.Message)
+		reply, err := dhcpv6.NewReplyFromMessage(received, dhcpv6.WithDNS(dns),
+			dhcpv6.WithOption(dhcpv6.OptInformationRefreshTime(time.Hour)))
+		if err != nil {
+			panic(err)
+		}
+		if _, err := conn.WriteTo(reply.ToBytes(), peer); err != nil {
+			panic(err)
+		}
+	}
+	s, err := server6.NewServer("", nil, handler, server6.WithConn(serverRawConn))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve()
+	}()
+	defer s.Close()
+
+	reply, err := mc.InformationRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, dhcpv6.MessageTypeReply, reply.Type())
+	require.Equal(t, []net.IP{dns}, reply.Options.DNS())
+	require.Equal(t, time.Hour, reply.Options.InformationRefreshTime(dhcpv6.IRTDefault))
+
+	require.Equal(t, dhcpv6.MessageTypeInformationRequest, received.Type())
+	require.Nil(t, received.Options.OneIANA())
+}
+
+// recordingLogger records the messages logged with Printf.
+type recordingLogger struct {
+	emptyLogger
+
+	mu   sync.Mutex
+	logs []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logs = append(l.logs, fmt.Sprintf(format, v...))
+}
+
+func TestFragmentationWarning(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		opts []ClientOpt
+		size int
+		want []string
+	}{
+		{
+			desc: "default, small message",
+			size: 100,
+		},
+		{
+			desc: "default, large message",
+			size: 2000,
+			want: []string{"Message of 2000 bytes is larger than 1232 bytes and may be fragmented"},
+		},
+		{
+			desc: "custom threshold",
+			opts: []ClientOpt{WithFragmentationWarning(50)},
+			size: 100,
+			want: []string{"Message of 100 bytes is larger than 50 bytes and may be fragmented"},
+		},
+		{
+			desc: "disabled",
+			opts: []ClientOpt{WithFragmentationWarning(0)},
+			size: 2000,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			clientRawConn, _, err := socketpair.PacketSocketPair()
+			require.NoError(t, err)
+
+			l := &recordingLogger{}
+			opts := append([]ClientOpt{func(c *Client) { c.logger = l }}, tt.opts...)
+			mc, err := NewWithConn(clientRawConn, net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}, opts...)
+			require.NoError(t, err)
+			defer mc.Close()
+
+			// The message header and option header take 8 bytes.
+			msg := newPacket([3]byte{0x55, 0x55, 0x55})
+			msg.AddOption(&dhcpv6.OptionGeneric{OptionCode: 65000, OptionData: make([]byte, tt.size-8)})
+			_, rem, err := mc.send(AllDHCPServers, msg)
+			require.NoError(t, err)
+			rem()
+
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			require.Equal(t, tt.want, l.logs)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package dhcpv6
+
+import (
+	"fmt"
+
+	"github.com/u-root/uio/uio"
+)
+
+// AuthProtocol is the authentication protocol of an Authentication option.
+type AuthProtocol uint8
+
+// Authentication protocols, as defined by RFC 8415 Section 20.4 and RFC
+// 3118.
+const (
+	AuthProtocolDelayed        AuthProtocol = 2
+	AuthProtocolReconfigureKey AuthProtocol = 3
+)
+
+func (p AuthProtocol) String() string {
+	switch p {
+	case AuthProtocolDelayed:
+		return "Delayed Authentication"
+	case AuthProtocolReconfigureKey:
+		return "Reconfigure Key"
+	default:
+		return fmt.Sprintf("unknown (%d)", p)
+	}
+}
+
+// AuthAlgorithm is the algorithm used to generate the authentication
+// information of an Authentication option.
+type AuthAlgorithm uint8
+
+// AuthAlgorithmHMACMD5 is the algorithm used by the Reconfigure Key
+// Authentication Protocol.
+const AuthAlgorithmHMACMD5 AuthAlgorithm = 1
+
+func (a AuthAlgorithm) String() string {
+	if a == AuthAlgorithmHMACMD5 {
+		return "HMAC-MD5"
+	}
+	return fmt.Sprintf("unknown (%d)", a)
+}
+
+// ReplayDetectionMethod is the type of the replay detection field of an
+// Authentication option.
+type ReplayDetectionMethod uint8
+
+// RDMMonotonicCounter is the only replay detection method defined by RFC
+// 8415: a value that increases for each message.
+const RDMMonotonicCounter ReplayDetectionMethod = 0
+
+func (m ReplayDetectionMethod) String() string {
+	if m == RDMMonotonicCounter {
+		return "Monotonic Counter"
+	}
+	return fmt.Sprintf("unknown (%d)", m)
+}
+
+// Types of authentication information carried with AuthProtocolReconfigureKey,
+// as defined by RFC 8415 Section 20.4.1.
+const (
+	ReconfigureKeyValue   uint8 = 1
+	ReconfigureKeyHMACMD5 uint8 = 2
+)
+
+// authMinimumLength is the length of the fixed fields of the option.
+const authMinimumLength = 11
+
+// OptAuth represents a DHCPv6 Authentication option as defined by RFC 8415
+// Section 21.11.
+//
+// Authentication information is kept as is: it is neither generated nor
+// verified.
+type OptAuth struct {
+	Protocol        AuthProtocol
+	Algorithm       AuthAlgorithm
+	RDM             ReplayDetectionMethod
+	ReplayDetection uint64
+	AuthInfo        []byte
+}
+
+// Code returns the option code
+func (op *OptAuth) Code() OptionCode {
+	return OptionAuth
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptAuth) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write8(uint8(op.Protocol))
+	buf.Write8(uint8(op.Algorithm))
+	buf.Write8(uint8(op.RDM))
+	buf.Write64(op.ReplayDetection)
+	buf.WriteBytes(op.AuthInfo)
+	return buf.Data()
+}
+
+func (op *OptAuth) String() string {
+	return fmt.Sprintf("%s: {Protocol=%s Algorithm=%s RDM=%s ReplayDetection=%#x AuthInfo=%#x}",
+		op.Code(), op.Protocol, op.Algorithm, op.RDM, op.ReplayDetection, op.AuthInfo)
+}
+
+// FromBytes builds an OptAuth structure from a sequence of bytes. The input
+// data does not include option code and length bytes.
+func (op *OptAuth) FromBytes(data []byte) error {
+	if len(data) < authMinimumLength {
+		return fmt.Errorf("%w: %s option needs at least %d bytes, got %d",
+			uio.ErrBufferTooShort, op.Code(), authMinimumLength, len(data))
+	}
+	buf := uio.NewBigEndianBuffer(data)
+	op.Protocol = AuthProtocol(buf.Read8())
+	op.Algorithm = AuthAlgorithm(buf.Read8())
+	op.RDM = ReplayDetectionMethod(buf.Read8())
+	op.ReplayDetection = buf.Read64()
+	op.AuthInfo = buf.ReadAll()
+	return buf.FinError()
+}
@@ -0,0 +1,62 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestAuthReconfigureKeyParseAndGetter(t *testing.T) {
+	key := []byte{
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+	}
+	buf := joinBytes([]byte{
+		0, 11, // Auth
+		0, 28, // length
+		3,                      // Reconfigure Key protocol
+		1,                      // HMAC-MD5
+		0,                      // Monotonic Counter
+		0, 0, 0, 0, 0, 0, 0, 5, // replay detection
+		1, // Reconfigure Key value
+	}, key)
+
+	var mo MessageOptions
+	require.Nil(t, mo.Auth())
+	require.NoError(t, mo.FromBytes(buf))
+
+	want := &OptAuth{
+		Protocol:        AuthProtocolReconfigureKey,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: 5,
+		AuthInfo:        append([]byte{ReconfigureKeyValue}, key...),
+	}
+	require.Equal(t, want, mo.Auth())
+
+	var m MessageOptions
+	m.Add(want)
+	require.Equal(t, buf, m.ToBytes())
+}
+
+func TestAuthParseInvalid(t *testing.T) {
+	var mo MessageOptions
+	err := mo.FromBytes([]byte{0, 11, 0, 10, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0})
+	require.True(t, errors.Is(err, uio.ErrBufferTooShort), "FromBytes = %v", err)
+}
+
+func TestOptAuthString(t *testing.T) {
+	opt := &OptAuth{
+		Protocol:        AuthProtocolReconfigureKey,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: 5,
+		AuthInfo:        []byte{1, 0xab},
+	}
+	require.Equal(t,
+		"Auth: {Protocol=Reconfigure Key Algorithm=HMAC-MD5 RDM=Monotonic Counter ReplayDetection=0x5 AuthInfo=0x01ab}",
+		opt.String())
+	require.Equal(t, "unknown (9)", AuthProtocol(9).String())
+}
@@ -2,6 +2,7 @@ package dhcpv6
 
 import (
 	"fmt"
+	"unicode/utf8"
 )
 
 // OptBootFileURL returns a OptionBootfileURL as defined by RFC 5970.
@@ -30,6 +31,9 @@ func (op optBootFileURL) String() string {
 // FromBytes builds an optBootFileURL structure from a sequence
 // of bytes. The input data does not include option code and length bytes.
 func (op *optBootFileURL) FromBytes(data []byte) error {
+	if !utf8.Valid(data) {
+		return fmt.Errorf("boot file URL is not valid UTF-8: %#x", data)
+	}
 	op.url = string(data)
 	return nil
 }
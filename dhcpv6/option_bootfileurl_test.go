@@ -57,3 +57,13 @@ func TestOptBootFileURL(t *testing.T) {
 	opt := OptBootFileURL("https://insomniac.slackware.it")
 	require.Contains(t, opt.String(), "https://insomniac.slackware.it", "String() should contain the correct BootFileUrl output")
 }
+
+func TestBootFileURLInvalidUTF8(t *testing.T) {
+	var mo MessageOptions
+	err := mo.FromBytes([]byte{
+		0, 59, // Boot File URL
+		0, 4, // length
+		'h', 't', 0xff, 'p',
+	})
+	require.Error(t, err)
+}
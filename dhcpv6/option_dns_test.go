@@ -39,6 +39,20 @@ func TestDNSParseAndGetter(t *testing.T) {
 				net.IP{0x2a, 0x03, 0x28, 0x80, 0xff, 0xfe, 0x00, 0x0c, 0xfa, 0xce, 0xb0, 0x0c, 0x00, 0x00, 0x00, 0x35},
 			},
 		},
+		{
+			buf: []byte{
+				0, 23, // DNS
+				0, 48, // length
+				0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+				0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02,
+				0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x03,
+			},
+			want: []net.IP{
+				net.ParseIP("2001:db8::1"),
+				net.ParseIP("2001:db8::2"),
+				net.ParseIP("2001:db8::3"),
+			},
+		},
 		{
 			buf:  nil,
 			want: nil,
@@ -82,3 +82,29 @@ func TestOptDomainSearchListString(t *testing.T) {
 	})
 	require.Contains(t, opt.String(), "example.com subnet.example.org", "String() should contain the correct domain search output")
 }
+
+func TestDomainSearchListCompressed(t *testing.T) {
+	buf := []byte{
+		0, 24, // Domain Search List
+		0, 19, // length
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		3, 's', 'u', 'b', 0xc0, 0, // pointer to "example.com" at offset 0
+	}
+	var mo MessageOptions
+	require.NoError(t, mo.FromBytes(buf))
+	require.Equal(t, []string{"example.com", "sub.example.com"}, mo.DomainSearchList().Labels)
+
+	// Parsed labels are written back as received, still compressed.
+	require.Equal(t, buf, mo.ToBytes())
+
+	// Labels that were not parsed are written uncompressed, with the option
+	// length counting every label length byte.
+	var m MessageOptions
+	m.Add(OptDomainSearchList(&rfc1035label.Labels{Labels: []string{"example.com", "sub.example.com"}}))
+	require.Equal(t, []byte{
+		0, 24, // Domain Search List
+		0, 30, // length
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		3, 's', 'u', 'b', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}, m.ToBytes())
+}
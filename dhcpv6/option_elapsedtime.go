@@ -2,6 +2,7 @@ package dhcpv6
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/u-root/uio/uio"
@@ -22,9 +23,19 @@ func (*optElapsedTime) Code() OptionCode {
 }
 
 // ToBytes marshals this option to bytes.
+//
+// The elapsed time is sent in hundredths of a second. Durations that do not
+// fit are sent as 0xffff, as required by RFC 8415 Section 21.9.
 func (op *optElapsedTime) ToBytes() []byte {
 	buf := uio.NewBigEndianBuffer(nil)
-	buf.Write16(uint16(op.ElapsedTime.Round(10*time.Millisecond) / (10 * time.Millisecond)))
+	cs := op.ElapsedTime.Round(10*time.Millisecond) / (10 * time.Millisecond)
+	switch {
+	case cs < 0:
+		cs = 0
+	case cs > math.MaxUint16:
+		cs = math.MaxUint16
+	}
+	buf.Write16(uint16(cs))
 	return buf.Data()
 }
 
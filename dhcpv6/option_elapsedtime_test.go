@@ -74,3 +74,21 @@ func TestOptElapsedTimeString(t *testing.T) {
 		t.Fatalf("Invalid elapsed time string. Expected %v, got %v", expected, optString)
 	}
 }
+
+func TestOptElapsedTimeClamp(t *testing.T) {
+	for _, tt := range []struct {
+		dur  time.Duration
+		want []byte
+	}{
+		{dur: 655350 * time.Millisecond, want: []byte{0xff, 0xff}},
+		{dur: 655360 * time.Millisecond, want: []byte{0xff, 0xff}},
+		{dur: time.Hour, want: []byte{0xff, 0xff}},
+		{dur: -time.Second, want: []byte{0, 0}},
+	} {
+		t.Run(tt.dur.String(), func(t *testing.T) {
+			if got := OptElapsedTime(tt.dur).ToBytes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToBytes = %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,8 +7,21 @@ import (
 	"github.com/u-root/uio/uio"
 )
 
+// Flags of the Client FQDN option, as defined by RFC 4704 Section 4.1.
+const (
+	// FQDNFlagS asks the server to perform the AAAA record update.
+	FQDNFlagS uint8 = 1 << 0
+	// FQDNFlagO is set by the server when it overrode the client's S
+	// flag.
+	FQDNFlagO uint8 = 1 << 1
+	// FQDNFlagN asks the server not to perform any DNS update.
+	FQDNFlagN uint8 = 1 << 2
+)
+
 // OptFQDN implements OptionFQDN option.
 //
+// Flags is a combination of the FQDNFlag* bits.
+//
 // https://tools.ietf.org/html/rfc4704
 type OptFQDN struct {
 	Flags      uint8
@@ -86,3 +86,23 @@ func TestOptFQDNString(t *testing.T) {
 	}
 	require.Equal(t, "FQDN: {Flags=0 DomainName=[cnos.localhost]}", opt.String())
 }
+
+func TestOptFQDNFlagsRoundTrip(t *testing.T) {
+	opt := &OptFQDN{
+		Flags: FQDNFlagS | FQDNFlagO,
+		DomainName: &rfc1035label.Labels{
+			Labels: []string{"host.example.com"},
+		},
+	}
+	want := []byte{
+		3, // flags: S and O
+		4, 'h', 'o', 's', 't', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	require.Equal(t, want, opt.ToBytes())
+
+	var got OptFQDN
+	require.NoError(t, got.FromBytes(want))
+	require.Equal(t, FQDNFlagS|FQDNFlagO, got.Flags)
+	require.Zero(t, got.Flags&FQDNFlagN)
+	require.Equal(t, []string{"host.example.com"}, got.DomainName.Labels)
+}
@@ -28,10 +28,8 @@ func (ao AddressOptions) Status() *OptStatusCode {
 	return sc
 }
 
-// OptIAAddress represents an OptionIAAddr.
-//
-// This module defines the OptIAAddress structure.
-// https://www.ietf.org/rfc/rfc3633.txt
+// OptIAAddress represents an OptionIAAddr as defined by RFC 8415 Section
+// 21.6. It is carried inside IA_NA and IA_TA options.
 type OptIAAddress struct {
 	IPv6Addr          net.IP
 	PreferredLifetime time.Duration
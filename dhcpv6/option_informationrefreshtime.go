@@ -7,6 +7,16 @@ import (
 	"github.com/u-root/uio/uio"
 )
 
+// Information Refresh Time bounds, as defined by RFC 8415 Section 7.6.
+const (
+	// IRTDefault is the Information Refresh Time that clients use when the
+	// server did not send one (IRT_DEFAULT).
+	IRTDefault = 86400 * time.Second
+	// IRTMinimum is the smallest Information Refresh Time that clients use
+	// (IRT_MINIMUM).
+	IRTMinimum = 600 * time.Second
+)
+
 // OptInformationRefreshTime implements OptionInformationRefreshTime option.
 // https://tools.ietf.org/html/rfc8415#section-21.23
 func OptInformationRefreshTime(irt time.Duration) *optInformationRefreshTime {
@@ -22,9 +22,9 @@ func TestInformationRefreshTimeParseAndGetter(t *testing.T) {
 			buf: []byte{
 				0, 32, // IRT option
 				0, 4, // length
-				0, 0, 0, 3,
+				0, 0, 0x0e, 0x10,
 			},
-			want: 3 * time.Second,
+			want: time.Hour,
 		},
 		{
 			buf: []byte{
@@ -64,6 +64,25 @@ func TestInformationRefreshTimeParseAndGetter(t *testing.T) {
 	}
 }
 
+func TestInformationRefreshTimeMinimum(t *testing.T) {
+	var mo MessageOptions
+	mo.Add(OptInformationRefreshTime(3 * time.Second))
+	if got := mo.InformationRefreshTime(0); got != IRTMinimum {
+		t.Errorf("InformationRefreshTime = %v, want %v", got, IRTMinimum)
+	}
+	// The option itself keeps the value sent by the server.
+	if got := mo.ToBytes(); !bytes.Equal(got, []byte{0, 32, 0, 4, 0, 0, 0, 3}) {
+		t.Errorf("ToBytes = %v, want the original value", got)
+	}
+}
+
+func TestInformationRefreshTimeDefault(t *testing.T) {
+	var mo MessageOptions
+	if got := mo.InformationRefreshTime(IRTDefault); got != 24*time.Hour {
+		t.Errorf("InformationRefreshTime = %v, want %v", got, 24*time.Hour)
+	}
+}
+
 func TestOptInformationRefreshTime(t *testing.T) {
 	var opt optInformationRefreshTime
 	err := opt.FromBytes([]byte{0xaa, 0xbb, 0xcc, 0xdd})
@@ -23,7 +23,7 @@ func (op *optInterfaceID) ToBytes() []byte {
 }
 
 func (op *optInterfaceID) String() string {
-	return fmt.Sprintf("%s: %v", op.Code(), op.ID)
+	return fmt.Sprintf("%s: %#x", op.Code(), op.ID)
 }
 
 // FromBytes builds an optInterfaceID structure from a sequence of bytes. The
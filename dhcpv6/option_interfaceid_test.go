@@ -59,10 +59,10 @@ func TestInterfaceIDParseAndGetter(t *testing.T) {
 
 func TestOptInterfaceID(t *testing.T) {
 	opt := OptInterfaceID([]byte("DSLAM01 eth2/1/01/21"))
-	require.Contains(
+	require.Equal(
 		t,
+		"Interface ID: 0x44534c414d303120657468322f312f30312f3231",
 		opt.String(),
-		"68 83 76 65 77 48 49 32 101 116 104 50 47 49 47 48 49 47 50 49",
-		"String() should return the interfaceId as bytes",
+		"String() should return the interfaceId in hex",
 	)
 }
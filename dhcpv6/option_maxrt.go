@@ -0,0 +1,68 @@
+package dhcpv6
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/u-root/uio/uio"
+)
+
+// Bounds of the SOL_MAX_RT and INF_MAX_RT values, as defined by RFC 8415
+// Sections 21.24 and 21.25.
+const (
+	MaxRTMinimum = 60 * time.Second
+	MaxRTMaximum = 86400 * time.Second
+)
+
+// OptSolMaxRT returns a SOL_MAX_RT option as defined by RFC 8415 Section
+// 21.24. It overrides the maximum retransmission timeout of SOLICIT messages.
+func OptSolMaxRT(d time.Duration) Option {
+	return &optMaxRT{code: OptionSolMaxRT, MaxRT: d}
+}
+
+// OptInfMaxRT returns an INF_MAX_RT option as defined by RFC 8415 Section
+// 21.25. It overrides the maximum retransmission timeout of
+// INFORMATION-REQUEST messages.
+func OptInfMaxRT(d time.Duration) Option {
+	return &optMaxRT{code: OptionInfMaxRT, MaxRT: d}
+}
+
+// optMaxRT implements both SOL_MAX_RT and INF_MAX_RT, which only differ by
+// their code.
+type optMaxRT struct {
+	code  OptionCode
+	MaxRT time.Duration
+}
+
+func (op *optMaxRT) Code() OptionCode {
+	return op.code
+}
+
+// ToBytes returns the option serialized to bytes.
+func (op *optMaxRT) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	Duration{op.MaxRT}.Marshal(buf)
+	return buf.Data()
+}
+
+func (op *optMaxRT) String() string {
+	return fmt.Sprintf("%s: %v", op.Code(), op.MaxRT)
+}
+
+// FromBytes builds an optMaxRT structure from a sequence of bytes. The input
+// data does not include option code and length bytes.
+//
+// Values outside of [MaxRTMinimum, MaxRTMaximum] are parsed, so that the
+// message can still be used, but are ignored by the MessageOptions getters.
+func (op *optMaxRT) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	var d Duration
+	d.Unmarshal(buf)
+	op.MaxRT = d.Duration
+	return buf.FinError()
+}
+
+// isValid reports whether the value is within the bounds set by RFC 8415.
+func (op *optMaxRT) isValid() bool {
+	return op.MaxRT >= MaxRTMinimum && op.MaxRT <= MaxRTMaximum
+}
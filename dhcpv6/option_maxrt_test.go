@@ -0,0 +1,67 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestMaxRTParseAndGetter(t *testing.T) {
+	buf := []byte{
+		0, 82, // SOL_MAX_RT
+		0, 4, // length
+		0, 0, 0x0e, 0x10, // 3600 seconds
+		0, 83, // INF_MAX_RT
+		0, 4, // length
+		0, 0, 0, 120, // 120 seconds
+	}
+	var mo MessageOptions
+	require.NoError(t, mo.FromBytes(buf))
+	require.Equal(t, time.Hour, mo.SolMaxRT(0))
+	require.Equal(t, 2*time.Minute, mo.InfMaxRT(0))
+
+	var m MessageOptions
+	m.Add(OptSolMaxRT(time.Hour))
+	m.Add(OptInfMaxRT(2 * time.Minute))
+	require.Equal(t, buf, m.ToBytes())
+}
+
+func TestMaxRTOutOfRange(t *testing.T) {
+	def := 42 * time.Second
+	for _, d := range []time.Duration{0, MaxRTMinimum - time.Second, MaxRTMaximum + time.Second} {
+		var mo MessageOptions
+		mo.Add(OptSolMaxRT(d))
+		mo.Add(OptInfMaxRT(d))
+		require.Equal(t, def, mo.SolMaxRT(def), "SOL_MAX_RT %v", d)
+		require.Equal(t, def, mo.InfMaxRT(def), "INF_MAX_RT %v", d)
+	}
+
+	// Out of range values are parsed, but ignored by the getters.
+	var parsed MessageOptions
+	require.NoError(t, parsed.FromBytes([]byte{
+		0, 82, 0, 4, 0, 0, 0, 59, // SOL_MAX_RT, 59 seconds
+		0, 83, 0, 4, 0, 1, 0x51, 0x81, // INF_MAX_RT, 86401 seconds
+	}))
+	require.Equal(t, def, parsed.SolMaxRT(def))
+	require.Equal(t, def, parsed.InfMaxRT(def))
+
+	var mo MessageOptions
+	mo.Add(OptSolMaxRT(MaxRTMinimum))
+	mo.Add(OptInfMaxRT(MaxRTMaximum))
+	require.Equal(t, MaxRTMinimum, mo.SolMaxRT(def))
+	require.Equal(t, MaxRTMaximum, mo.InfMaxRT(def))
+}
+
+func TestMaxRTInvalidLength(t *testing.T) {
+	var mo MessageOptions
+	err := mo.FromBytes([]byte{0, 82, 0, 2, 0, 60})
+	require.True(t, errors.Is(err, uio.ErrBufferTooShort), "FromBytes = %v", err)
+}
+
+func TestOptMaxRTString(t *testing.T) {
+	require.Equal(t, "Max Solicit Timeout Value: 1h0m0s", OptSolMaxRT(time.Hour).String())
+	require.Equal(t, "Max Information-Request Timeout Value: 2m0s", OptInfMaxRT(2*time.Minute).String())
+}
@@ -108,6 +108,19 @@ type OptNTPServer struct {
 	Suboptions Options
 }
 
+// AddServerAddress adds a server address sub-option for ip.
+func (op *OptNTPServer) AddServerAddress(ip net.IP) {
+	so := NTPSuboptionSrvAddr(ip)
+	op.Suboptions.Add(&so)
+}
+
+// AddServerFQDN adds a server FQDN sub-option for the domain name fqdn.
+func (op *OptNTPServer) AddServerFQDN(fqdn string) {
+	op.Suboptions.Add(&NTPSuboptionSrvFQDN{
+		Labels: rfc1035label.Labels{Labels: []string{fqdn}},
+	})
+}
+
 // Code returns the option code
 func (op *OptNTPServer) Code() OptionCode {
 	return OptionNTPServer
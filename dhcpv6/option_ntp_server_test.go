@@ -85,3 +85,68 @@ func TestParseOptNTPServer(t *testing.T) {
 	// MessageOptions.NTPServers only returns server address values.
 	assert.Equal(t, []net.IP{ip}, mo.NTPServers())
 }
+
+func TestOptNTPServerRoundTrip(t *testing.T) {
+	srv := net.ParseIP("2001:db8::123")
+	mc := net.ParseIP("ff05::101")
+	fqdn := rfc1035label.NewLabels()
+	fqdn.Labels = []string{"ntp.example.com"}
+
+	o := &OptNTPServer{}
+	o.Suboptions.Add((*NTPSuboptionSrvAddr)(&srv))
+	o.Suboptions.Add((*NTPSuboptionMCAddr)(&mc))
+	o.Suboptions.Add(&NTPSuboptionSrvFQDN{*fqdn})
+
+	want := []byte{
+		0x00, 0x01, // server address sub-option
+		0x00, 0x10, // length
+	}
+	want = append(want, srv...)
+	want = append(want, []byte{
+		0x00, 0x02, // multicast address sub-option
+		0x00, 0x10, // length
+	}...)
+	want = append(want, mc...)
+	want = append(want, []byte{
+		0x00, 0x03, // server FQDN sub-option
+		0x00, 0x11, // length
+		3, 'n', 't', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}...)
+	require.Equal(t, want, o.ToBytes())
+
+	var got OptNTPServer
+	require.NoError(t, got.FromBytes(want))
+	require.Equal(t, 3, len(got.Suboptions))
+
+	optAddr, ok := got.Suboptions[0].(*NTPSuboptionSrvAddr)
+	require.True(t, ok)
+	assert.Equal(t, srv, net.IP(*optAddr))
+
+	optMC, ok := got.Suboptions[1].(*NTPSuboptionMCAddr)
+	require.True(t, ok)
+	assert.Equal(t, mc, net.IP(*optMC))
+
+	optFQDN, ok := got.Suboptions[2].(*NTPSuboptionSrvFQDN)
+	require.True(t, ok)
+	assert.Equal(t, []string{"ntp.example.com"}, optFQDN.Labels.Labels)
+
+	assert.Equal(t, want, got.ToBytes())
+}
+
+func TestOptNTPServerAdd(t *testing.T) {
+	ip := net.ParseIP("2001:db8::123")
+	var o OptNTPServer
+	o.AddServerAddress(ip)
+	o.AddServerFQDN("ntp.example.com")
+	require.Equal(t, "NTP: [Server Address: 2001:db8::123 Server FQDN: [ntp.example.com]]", o.String())
+
+	var got OptNTPServer
+	require.NoError(t, got.FromBytes(o.ToBytes()))
+	require.Equal(t, 2, len(got.Suboptions))
+	optAddr, ok := got.Suboptions[0].(*NTPSuboptionSrvAddr)
+	require.True(t, ok)
+	assert.Equal(t, ip, net.IP(*optAddr))
+	optFQDN, ok := got.Suboptions[1].(*NTPSuboptionSrvFQDN)
+	require.True(t, ok)
+	assert.Equal(t, []string{"ntp.example.com"}, optFQDN.Labels.Labels)
+}
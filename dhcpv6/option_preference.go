@@ -0,0 +1,41 @@
+package dhcpv6
+
+import (
+	"fmt"
+
+	"github.com/u-root/uio/uio"
+)
+
+// OptPreference returns a Preference option as defined by RFC 8415 Section
+// 21.8.
+//
+// Clients pick the ADVERTISE with the highest preference value. A value of 255
+// tells clients to stop waiting for other ADVERTISE messages.
+func OptPreference(pref uint8) Option {
+	return &optPreference{Preference: pref}
+}
+
+type optPreference struct {
+	Preference uint8
+}
+
+func (*optPreference) Code() OptionCode {
+	return OptionPreference
+}
+
+// ToBytes returns the option serialized to bytes.
+func (op *optPreference) ToBytes() []byte {
+	return []byte{op.Preference}
+}
+
+func (op *optPreference) String() string {
+	return fmt.Sprintf("%s: %d", op.Code(), op.Preference)
+}
+
+// FromBytes builds an optPreference structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func (op *optPreference) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	op.Preference = buf.Read8()
+	return buf.FinError()
+}
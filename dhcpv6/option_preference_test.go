@@ -0,0 +1,41 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestPreferenceParseAndGetter(t *testing.T) {
+	var mo MessageOptions
+	require.Equal(t, uint8(0), mo.Preference())
+
+	buf := []byte{
+		0, 7, // Preference
+		0, 1, // length
+		255,
+	}
+	require.NoError(t, mo.FromBytes(buf))
+	require.Equal(t, uint8(255), mo.Preference())
+
+	var m MessageOptions
+	m.Add(OptPreference(255))
+	require.Equal(t, buf, m.ToBytes())
+}
+
+func TestPreferenceInvalidLength(t *testing.T) {
+	for _, buf := range [][]byte{
+		{0, 7, 0, 0},
+		{0, 7, 0, 2, 1, 2},
+	} {
+		var mo MessageOptions
+		err := mo.FromBytes(buf)
+		require.True(t, errors.Is(err, uio.ErrBufferTooShort) || errors.Is(err, uio.ErrUnreadBytes), "FromBytes(%v) = %v", buf, err)
+	}
+}
+
+func TestOptPreferenceString(t *testing.T) {
+	require.Equal(t, "Preference: 10", OptPreference(10).String())
+}
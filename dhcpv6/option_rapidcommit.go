@@ -0,0 +1,37 @@
+package dhcpv6
+
+import (
+	"github.com/u-root/uio/uio"
+)
+
+// OptRapidCommit returns a Rapid Commit option as defined by RFC 8415 Section
+// 21.14.
+//
+// A client includes it in a SOLICIT to request the two-message exchange, and
+// a server includes it in the REPLY when committing the assigned leases
+// directly.
+func OptRapidCommit() Option {
+	return &optRapidCommit{}
+}
+
+type optRapidCommit struct{}
+
+func (*optRapidCommit) Code() OptionCode {
+	return OptionRapidCommit
+}
+
+// ToBytes returns the option payload, which is always empty.
+func (*optRapidCommit) ToBytes() []byte {
+	return nil
+}
+
+func (op *optRapidCommit) String() string {
+	return op.Code().String()
+}
+
+// FromBytes builds an optRapidCommit structure from a sequence of bytes. The
+// input data does not include option code and length bytes, and must be
+// empty.
+func (*optRapidCommit) FromBytes(data []byte) error {
+	return uio.NewBigEndianBuffer(data).FinError()
+}
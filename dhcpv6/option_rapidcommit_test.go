@@ -0,0 +1,41 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRapidCommit(t *testing.T) {
+	var opt optRapidCommit
+	require.NoError(t, opt.FromBytes(nil))
+	require.Error(t, opt.FromBytes([]byte{0x00}))
+}
+
+func TestRapidCommitToBytes(t *testing.T) {
+	m := Message{}
+	WithRapidCommit(&m)
+	require.True(t, m.Options.RapidCommit())
+	require.Equal(t, []byte{0x00, 0x0e, 0x00, 0x00}, m.Options.ToBytes())
+}
+
+func TestRapidCommitString(t *testing.T) {
+	require.Equal(t, "Rapid Commit", OptRapidCommit().String())
+}
+
+func TestParseMessageWithRapidCommit(t *testing.T) {
+	data := []byte{
+		1,                // SOLICIT
+		0xaa, 0xbb, 0xcc, // transaction ID
+		0, 14, 0, 0, // Rapid Commit option
+	}
+	m, err := MessageFromBytes(data)
+	require.NoError(t, err)
+	require.True(t, m.Options.RapidCommit())
+	require.Equal(t, OptRapidCommit(), m.GetOneOption(OptionRapidCommit))
+	require.Equal(t, data, m.ToBytes())
+
+	// a non-empty Rapid Commit option is malformed
+	_, err = MessageFromBytes([]byte{1, 0xaa, 0xbb, 0xcc, 0, 14, 0, 1, 0})
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package dhcpv6
+
+import (
+	"fmt"
+
+	"github.com/u-root/uio/uio"
+)
+
+// OptReconfigureMessage returns a Reconfigure Message option as defined by RFC
+// 8415 Section 21.19.
+//
+// A server includes it in a RECONFIGURE message to tell the client which
+// message to send in response: MessageTypeRenew, MessageTypeRebind or
+// MessageTypeInformationRequest.
+func OptReconfigureMessage(mt MessageType) Option {
+	return &optReconfigureMessage{MessageType: mt}
+}
+
+type optReconfigureMessage struct {
+	MessageType MessageType
+}
+
+func (*optReconfigureMessage) Code() OptionCode {
+	return OptionReconfMessage
+}
+
+// ToBytes returns the option serialized to bytes.
+func (op *optReconfigureMessage) ToBytes() []byte {
+	return []byte{byte(op.MessageType)}
+}
+
+func (op *optReconfigureMessage) String() string {
+	return fmt.Sprintf("%s: %s", op.Code(), op.MessageType)
+}
+
+// FromBytes builds an optReconfigureMessage structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func (op *optReconfigureMessage) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	op.MessageType = MessageType(buf.Read8())
+	if err := buf.FinError(); err != nil {
+		return err
+	}
+	switch op.MessageType {
+	case MessageTypeRenew, MessageTypeRebind, MessageTypeInformationRequest:
+		return nil
+	default:
+		return fmt.Errorf("invalid message type %s in %s option", op.MessageType, op.Code())
+	}
+}
+
+// OptReconfigureAccept returns a Reconfigure Accept option as defined by RFC
+// 8415 Section 21.20.
+//
+// A client includes it to tell the server that it is willing to accept
+// RECONFIGURE messages.
+func OptReconfigureAccept() Option {
+	return &optReconfigureAccept{}
+}
+
+type optReconfigureAccept struct{}
+
+func (*optReconfigureAccept) Code() OptionCode {
+	return OptionReconfAccept
+}
+
+// ToBytes returns the option payload, which is always empty.
+func (*optReconfigureAccept) ToBytes() []byte {
+	return nil
+}
+
+func (op *optReconfigureAccept) String() string {
+	return op.Code().String()
+}
+
+// FromBytes builds an optReconfigureAccept structure from a sequence of
+// bytes. The input data does not include option code and length bytes, and
+// must be empty.
+func (*optReconfigureAccept) FromBytes(data []byte) error {
+	return uio.NewBigEndianBuffer(data).FinError()
+}
@@ -0,0 +1,57 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestReconfigureMessageParseAndGetter(t *testing.T) {
+	var mo MessageOptions
+	require.Equal(t, MessageType(0), mo.ReconfigureMessage())
+
+	buf := []byte{
+		0, 19, // Reconfigure Message
+		0, 1, // length
+		11, // INFORMATION-REQUEST
+	}
+	require.NoError(t, mo.FromBytes(buf))
+	require.Equal(t, MessageTypeInformationRequest, mo.ReconfigureMessage())
+
+	var m MessageOptions
+	m.Add(OptReconfigureMessage(MessageTypeInformationRequest))
+	require.Equal(t, buf, m.ToBytes())
+}
+
+func TestReconfigureMessageInvalid(t *testing.T) {
+	var mo MessageOptions
+	require.Error(t, mo.FromBytes([]byte{0, 19, 0, 1, byte(MessageTypeSolicit)}))
+
+	mo = MessageOptions{}
+	err := mo.FromBytes([]byte{0, 19, 0, 2, 5, 0})
+	require.True(t, errors.Is(err, uio.ErrUnreadBytes), "FromBytes = %v", err)
+}
+
+func TestReconfigureAccept(t *testing.T) {
+	var mo MessageOptions
+	require.False(t, mo.ReconfigureAccept())
+
+	buf := []byte{0, 20, 0, 0}
+	require.NoError(t, mo.FromBytes(buf))
+	require.True(t, mo.ReconfigureAccept())
+
+	m, err := NewMessage(WithReconfigureAccept)
+	require.NoError(t, err)
+	require.Equal(t, buf, m.Options.ToBytes())
+
+	mo = MessageOptions{}
+	err = mo.FromBytes([]byte{0, 20, 0, 1, 0})
+	require.True(t, errors.Is(err, uio.ErrUnreadBytes), "FromBytes = %v", err)
+}
+
+func TestOptReconfigureString(t *testing.T) {
+	require.Equal(t, "Reconfig Message: RENEW", OptReconfigureMessage(MessageTypeRenew).String())
+	require.Equal(t, "Reconfig Accept", OptReconfigureAccept().String())
+}
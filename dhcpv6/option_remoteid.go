@@ -6,7 +6,7 @@ import (
 	"github.com/u-root/uio/uio"
 )
 
-// OptRemoteID implemens the Remote ID option as defined by RFC 4649.
+// OptRemoteID implements the Remote ID option as defined by RFC 4649.
 type OptRemoteID struct {
 	EnterpriseNumber uint32
 	RemoteID         []byte
@@ -108,3 +108,13 @@ func TestOptRequestedOptionString(t *testing.T) {
 		"String() should contain 'Unknown' for an illegal option",
 	)
 }
+
+func TestOptionCodesAdd(t *testing.T) {
+	var codes OptionCodes
+	codes.Add(OptionDNSRecursiveNameServer)
+	codes.Add(OptionDomainSearchList)
+	codes.Add(OptionDNSRecursiveNameServer)
+	require.Equal(t, OptionCodes{OptionDNSRecursiveNameServer, OptionDomainSearchList}, codes)
+	require.True(t, codes.Contains(OptionDomainSearchList))
+	require.False(t, codes.Contains(OptionNTPServer))
+}
@@ -0,0 +1,42 @@
+package dhcpv6
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/u-root/uio/uio"
+)
+
+// OptServerUnicast returns a Server Unicast option as defined by RFC 8415
+// Section 21.12.
+//
+// A server includes it to allow clients to unicast REQUEST, RENEW, RELEASE
+// and DECLINE messages to the given address.
+func OptServerUnicast(ip net.IP) Option {
+	return &optServerUnicast{ServerAddress: ip}
+}
+
+type optServerUnicast struct {
+	ServerAddress net.IP
+}
+
+func (*optServerUnicast) Code() OptionCode {
+	return OptionUnicast
+}
+
+// ToBytes returns the option serialized to bytes.
+func (op *optServerUnicast) ToBytes() []byte {
+	return op.ServerAddress.To16()
+}
+
+func (op *optServerUnicast) String() string {
+	return fmt.Sprintf("%s: %s", op.Code(), op.ServerAddress)
+}
+
+// FromBytes builds an optServerUnicast structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func (op *optServerUnicast) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	op.ServerAddress = net.IP(buf.CopyN(net.IPv6len))
+	return buf.FinError()
+}
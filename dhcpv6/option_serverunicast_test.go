@@ -0,0 +1,41 @@
+package dhcpv6
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestServerUnicastParseAndGetter(t *testing.T) {
+	var mo MessageOptions
+	require.Nil(t, mo.ServerUnicast())
+
+	ip := net.ParseIP("2001:db8::547")
+	buf := append([]byte{
+		0, 12, // Unicast
+		0, 16, // length
+	}, ip...)
+	require.NoError(t, mo.FromBytes(buf))
+	require.Equal(t, ip, mo.ServerUnicast())
+
+	var m MessageOptions
+	m.Add(OptServerUnicast(ip))
+	require.Equal(t, buf, m.ToBytes())
+}
+
+func TestServerUnicastInvalidLength(t *testing.T) {
+	var mo MessageOptions
+	err := mo.FromBytes([]byte{0, 12, 0, 4, 192, 0, 2, 1})
+	require.True(t, errors.Is(err, uio.ErrBufferTooShort), "FromBytes = %v", err)
+
+	mo = MessageOptions{}
+	err = mo.FromBytes(append([]byte{0, 12, 0, 17}, make([]byte, 17)...))
+	require.True(t, errors.Is(err, uio.ErrUnreadBytes), "FromBytes = %v", err)
+}
+
+func TestOptServerUnicastString(t *testing.T) {
+	require.Equal(t, "Unicast: 2001:db8::547", OptServerUnicast(net.ParseIP("2001:db8::547")).String())
+}
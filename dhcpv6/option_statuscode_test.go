@@ -65,6 +65,17 @@ func TestStatusCodeParseAndGetter(t *testing.T) {
 				StatusMessage: "use multicast",
 			},
 		},
+		{
+			buf: []byte{
+				0, 13, // StatusCode option
+				0, 2, // length
+				0, 2, // StatusNoAddrsAvail
+			},
+			want: &OptStatusCode{
+				StatusCode:    iana.StatusNoAddrsAvail,
+				StatusMessage: "",
+			},
+		},
 		{
 			buf:  nil,
 			want: nil,
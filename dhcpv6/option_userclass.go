@@ -3,6 +3,8 @@ package dhcpv6
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/u-root/uio/uio"
 )
@@ -30,14 +32,28 @@ func (op *OptUserClass) ToBytes() []byte {
 	return buf.Data()
 }
 
+// String returns the user classes as text, or in hex if they are not
+// printable.
 func (op *OptUserClass) String() string {
 	ucStrings := make([]string, 0, len(op.UserClasses))
 	for _, uc := range op.UserClasses {
-		ucStrings = append(ucStrings, string(uc))
+		ucStrings = append(ucStrings, userClassString(uc))
 	}
 	return fmt.Sprintf("%s: [%s]", op.Code(), strings.Join(ucStrings, ", "))
 }
 
+func userClassString(uc []byte) string {
+	if !utf8.Valid(uc) {
+		return fmt.Sprintf("%#x", uc)
+	}
+	for _, r := range string(uc) {
+		if !unicode.IsPrint(r) {
+			return fmt.Sprintf("%#x", uc)
+		}
+	}
+	return string(uc)
+}
+
 // FromBytes builds an OptUserClass structure from a sequence of bytes. The
 // input data does not include option code and length bytes.
 func (op *OptUserClass) FromBytes(data []byte) error {
@@ -77,3 +77,14 @@ func TestOptUserClassString(t *testing.T) {
 		"String() should contain the list of user classes",
 	)
 }
+
+func TestOptUserClassStringBinary(t *testing.T) {
+	opt := OptUserClass{
+		UserClasses: [][]byte{
+			[]byte("linuxboot"),
+			{0x00, 0x01, 0xff},
+			[]byte("tab\there"),
+		},
+	}
+	require.Equal(t, "User Class: [linuxboot, 0x0001ff, 0x7461620968657265]", opt.String())
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/u-root/uio/uio"
 )
 
-// OptVendorOpts represents a DHCPv6 Status Code option
+// OptVendorOpts represents a DHCPv6 Vendor-specific Information option
 //
 // This module defines the OptVendorOpts structure.
 // https://tools.ietf.org/html/rfc3315#section-22.17
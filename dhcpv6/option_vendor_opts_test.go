@@ -92,3 +92,16 @@ func TestVendorOptsParseAndGetter(t *testing.T) {
 		})
 	}
 }
+
+func TestOptVendorOptsString(t *testing.T) {
+	opt := &OptVendorOpts{
+		EnterpriseNumber: 0xaaaa,
+		VendorOpts: Options{
+			&OptionGeneric{OptionCode: 1000, OptionData: []byte("xyz")},
+		},
+	}
+	want := "Vendor Options: {EnterpriseNumber=43690 VendorOptions=[unknown (1000): [120 121 122]]}"
+	if got := opt.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/insomniacslk/dhcp/internal/truncate"
 	"github.com/u-root/uio/uio"
 )
 
@@ -15,6 +16,9 @@ type Option interface {
 	FromBytes([]byte) error
 }
 
+// OptionGeneric is an option with an opaque payload. ParseOption returns it for
+// option codes it does not know, so that unknown options are preserved when a
+// message is parsed and marshaled again.
 type OptionGeneric struct {
 	OptionCode OptionCode
 	OptionData []byte
@@ -28,11 +32,21 @@ func (og *OptionGeneric) ToBytes() []byte {
 	return og.OptionData
 }
 
+// MaxOptionDataString is the number of bytes of raw data printed by the String
+// method of OptionGeneric, which is used for unknown options. Longer data is
+// truncated and followed by its total length. A value of zero or less
+// disables truncation.
+//
+// dhcpv4.MaxOptionDataString is the same setting for DHCPv4 options, with the
+// same default.
+var MaxOptionDataString = truncate.DefaultMax
+
+// String prints the option data, truncated to MaxOptionDataString bytes.
 func (og *OptionGeneric) String() string {
 	if len(og.OptionData) == 0 {
 		return og.OptionCode.String()
 	}
-	return fmt.Sprintf("%s: %v", og.OptionCode, og.OptionData)
+	return fmt.Sprintf("%s: %s", og.OptionCode, truncate.Bytes(og.OptionData, MaxOptionDataString))
 }
 
 // FromBytes resets OptionData to p.
@@ -41,78 +55,72 @@ func (og *OptionGeneric) FromBytes(p []byte) error {
 	return nil
 }
 
+// optionParsers maps option codes to a function returning a new, empty
+// option of the matching type.
+var optionParsers = map[OptionCode]func() Option{
+	OptionClientID:               func() Option { return &optClientID{} },
+	OptionServerID:               func() Option { return &optServerID{} },
+	OptionIANA:                   func() Option { return &OptIANA{} },
+	OptionIATA:                   func() Option { return &OptIATA{} },
+	OptionIAAddr:                 func() Option { return &OptIAAddress{} },
+	OptionORO:                    func() Option { return &optRequestedOption{} },
+	OptionPreference:             func() Option { return &optPreference{} },
+	OptionElapsedTime:            func() Option { return &optElapsedTime{} },
+	OptionRelayMsg:               func() Option { return &optRelayMsg{} },
+	OptionUnicast:                func() Option { return &optServerUnicast{} },
+	OptionStatusCode:             func() Option { return &OptStatusCode{} },
+	OptionAuth:                   func() Option { return &OptAuth{} },
+	OptionRapidCommit:            func() Option { return &optRapidCommit{} },
+	OptionReconfMessage:          func() Option { return &optReconfigureMessage{} },
+	OptionReconfAccept:           func() Option { return &optReconfigureAccept{} },
+	OptionUserClass:              func() Option { return &OptUserClass{} },
+	OptionVendorClass:            func() Option { return &OptVendorClass{} },
+	OptionVendorOpts:             func() Option { return &OptVendorOpts{} },
+	OptionInterfaceID:            func() Option { return &optInterfaceID{} },
+	OptionDNSRecursiveNameServer: func() Option { return &optDNS{} },
+	OptionDomainSearchList:       func() Option { return &optDomainSearchList{} },
+	OptionIAPD:                   func() Option { return &OptIAPD{} },
+	OptionIAPrefix:               func() Option { return &OptIAPrefix{} },
+	OptionInformationRefreshTime: func() Option { return &optInformationRefreshTime{} },
+	OptionRemoteID:               func() Option { return &OptRemoteID{} },
+	OptionFQDN:                   func() Option { return &OptFQDN{} },
+	OptionNTPServer:              func() Option { return &OptNTPServer{} },
+	OptionBootfileURL:            func() Option { return &optBootFileURL{} },
+	OptionBootfileParam:          func() Option { return &optBootFileParam{} },
+	OptionClientArchType:         func() Option { return &optClientArchType{} },
+	OptionNII:                    func() Option { return &OptNetworkInterfaceID{} },
+	OptionClientLinkLayerAddr:    func() Option { return &optClientLinkLayerAddress{} },
+	OptionDHCPv4Msg:              func() Option { return &OptDHCPv4Msg{} },
+	OptionDHCP4oDHCP6Server:      func() Option { return &OptDHCP4oDHCP6Server{} },
+	Option4RD:                    func() Option { return &Opt4RD{} },
+	Option4RDMapRule:             func() Option { return &Opt4RDMapRule{} },
+	Option4RDNonMapRule:          func() Option { return &Opt4RDNonMapRule{} },
+	OptionSolMaxRT:               func() Option { return &optMaxRT{code: OptionSolMaxRT} },
+	OptionInfMaxRT:               func() Option { return &optMaxRT{code: OptionInfMaxRT} },
+	OptionRelayPort:              func() Option { return &optRelayPort{} },
+}
+
+// RegisterOption makes ParseOption use newOption to parse options with the
+// given code, replacing any previous registration. newOption must return a
+// new, empty option on each call.
+//
+// RegisterOption is meant to be called from init functions. It is not safe
+// to call concurrently with parsing.
+func RegisterOption(code OptionCode, newOption func() Option) {
+	optionParsers[code] = newOption
+}
+
 // ParseOption parses data according to the given code.
 //
 // Parse a sequence of bytes as a single DHCPv6 option.
-// Returns the option structure, or an error if any.
+// Returns the option structure, or an error if any. Options with a code that
+// is neither known nor registered with RegisterOption are returned as
+// *OptionGeneric.
 func ParseOption(code OptionCode, optData []byte) (Option, error) {
 	var opt Option
-	switch code {
-	case OptionClientID:
-		opt = &optClientID{}
-	case OptionServerID:
-		opt = &optServerID{}
-	case OptionIANA:
-		opt = &OptIANA{}
-	case OptionIATA:
-		opt = &OptIATA{}
-	case OptionIAAddr:
-		opt = &OptIAAddress{}
-	case OptionORO:
-		opt = &optRequestedOption{}
-	case OptionElapsedTime:
-		opt = &optElapsedTime{}
-	case OptionRelayMsg:
-		opt = &optRelayMsg{}
-	case OptionStatusCode:
-		opt = &OptStatusCode{}
-	case OptionUserClass:
-		opt = &OptUserClass{}
-	case OptionVendorClass:
-		opt = &OptVendorClass{}
-	case OptionVendorOpts:
-		opt = &OptVendorOpts{}
-	case OptionInterfaceID:
-		opt = &optInterfaceID{}
-	case OptionDNSRecursiveNameServer:
-		opt = &optDNS{}
-	case OptionDomainSearchList:
-		opt = &optDomainSearchList{}
-	case OptionIAPD:
-		opt = &OptIAPD{}
-	case OptionIAPrefix:
-		opt = &OptIAPrefix{}
-	case OptionInformationRefreshTime:
-		opt = &optInformationRefreshTime{}
-	case OptionRemoteID:
-		opt = &OptRemoteID{}
-	case OptionFQDN:
-		opt = &OptFQDN{}
-	case OptionNTPServer:
-		opt = &OptNTPServer{}
-	case OptionBootfileURL:
-		opt = &optBootFileURL{}
-	case OptionBootfileParam:
-		opt = &optBootFileParam{}
-	case OptionClientArchType:
-		opt = &optClientArchType{}
-	case OptionNII:
-		opt = &OptNetworkInterfaceID{}
-	case OptionClientLinkLayerAddr:
-		opt = &optClientLinkLayerAddress{}
-	case OptionDHCPv4Msg:
-		opt = &OptDHCPv4Msg{}
-	case OptionDHCP4oDHCP6Server:
-		opt = &OptDHCP4oDHCP6Server{}
-	case Option4RD:
-		opt = &Opt4RD{}
-	case Option4RDMapRule:
-		opt = &Opt4RDMapRule{}
-	case Option4RDNonMapRule:
-		opt = &Opt4RDNonMapRule{}
-	case OptionRelayPort:
-		opt = &optRelayPort{}
-	default:
+	if newOption, ok := optionParsers[code]; ok {
+		opt = newOption()
+	} else {
 		opt = &OptionGeneric{OptionCode: code}
 	}
 	return opt, opt.FromBytes(optData)
@@ -199,6 +207,16 @@ func (o *Options) Update(option Option) {
 	o.Add(option)
 }
 
+// Length returns the length of the options once marshaled, including the
+// option code and length fields.
+func (o Options) Length() int {
+	var l int
+	for _, opt := range o {
+		l += 4 + len(opt.ToBytes())
+	}
+	return l
+}
+
 // ToBytes marshals all options to bytes.
 func (o Options) ToBytes() []byte {
 	buf := uio.NewBigEndianBuffer(nil)
@@ -234,8 +252,13 @@ func (o *Options) FromBytesWithParser(data []byte, parser OptionParser) error {
 
 	buf := uio.NewBigEndianBuffer(data)
 	for buf.Has(4) {
+		offset := len(data) - buf.Len()
 		code := OptionCode(buf.Read16())
 		length := int(buf.Read16())
+		if !buf.Has(length) {
+			return fmt.Errorf("%w: option %s at offset %d has length %d, but only %d bytes remain",
+				uio.ErrBufferTooShort, code, offset, length, buf.Len())
+		}
 
 		// Consume, but do not Copy. Each parser will make a copy of
 		// pertinent data.
@@ -0,0 +1,118 @@
+package dhcpv6
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/u-root/uio/uio"
+)
+
+func TestParseOptionUnknown(t *testing.T) {
+	opt, err := ParseOption(OptionCode(65000), []byte{0xa, 0xb, 0xc})
+	require.NoError(t, err)
+	require.Equal(t, &OptionGeneric{OptionCode: 65000, OptionData: []byte{0xa, 0xb, 0xc}}, opt)
+}
+
+func TestUnknownOptionRoundTrip(t *testing.T) {
+	data := []byte{
+		1,                // SOLICIT
+		0xaa, 0xbb, 0xcc, // transaction ID
+		0xfd, 0xe8, 0, 3, // unknown option 65000, length 3
+		0xa, 0xb, 0xc,
+		0, 8, 0, 2, // Elapsed Time
+		0, 0,
+	}
+	m, err := MessageFromBytes(data)
+	require.NoError(t, err)
+
+	opt, ok := m.GetOneOption(OptionCode(65000)).(*OptionGeneric)
+	require.True(t, ok)
+	require.Equal(t, []byte{0xa, 0xb, 0xc}, opt.OptionData)
+	require.Equal(t, data, m.ToBytes())
+}
+
+func TestParseOptionTruncated(t *testing.T) {
+	// A buffer that is valid-looking enough to get deep into most parsers:
+	// nested option headers with large lengths, label lengths and
+	// addresses.
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i % 8)
+	}
+	for code := range optionCodeToString {
+		for n := 0; n <= len(data); n++ {
+			// Each parser must return an error or succeed, never panic.
+			_, _ = ParseOption(code, data[:n])
+		}
+	}
+}
+
+func TestOptionsFromBytesLengthTooLong(t *testing.T) {
+	for _, data := range [][]byte{
+		{0, 23, 0, 16, 0x20, 0x01},        // DNS, length 16, 2 bytes of data
+		{0, 8, 0xff, 0xff, 0},             // Elapsed Time, length 65535
+		{0xfd, 0xe8, 0, 4, 0xa, 0xb, 0xc}, // unknown option, one byte short
+		{0, 1, 0},                         // truncated header
+	} {
+		var o Options
+		require.Error(t, o.FromBytes(data), "data %v", data)
+	}
+}
+
+func TestOptionsFromBytesLengthError(t *testing.T) {
+	data := []byte{
+		0, 8, 0, 2, 0, 0, // Elapsed Time
+		0, 23, 0, 16, 0x20, 0x01, // DNS, claiming 16 bytes with 2 present
+	}
+	var o Options
+	err := o.FromBytes(data)
+	require.True(t, errors.Is(err, uio.ErrBufferTooShort), "FromBytes = %v", err)
+	require.Contains(t, err.Error(), "option DNS")
+	require.Contains(t, err.Error(), "offset 6")
+	require.Contains(t, err.Error(), "length 16")
+}
+
+// optTest is a custom option registered by TestRegisterOption.
+type optTest struct {
+	Value uint8
+}
+
+func (*optTest) Code() OptionCode { return 65001 }
+
+func (op *optTest) ToBytes() []byte { return []byte{op.Value} }
+
+func (op *optTest) String() string { return "test" }
+
+func (op *optTest) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	op.Value = buf.Read8()
+	return buf.FinError()
+}
+
+func TestRegisterOption(t *testing.T) {
+	RegisterOption(65001, func() Option { return &optTest{} })
+	defer delete(optionParsers, 65001)
+
+	data := []byte{
+		7,                // REPLY
+		0xaa, 0xbb, 0xcc, // transaction ID
+		0xfd, 0xe9, 0, 1, // option 65001, length 1
+		42,
+	}
+	m, err := MessageFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, &optTest{Value: 42}, m.GetOneOption(65001))
+	require.Equal(t, data, m.ToBytes())
+
+	_, err = ParseOption(65001, []byte{1, 2})
+	require.True(t, errors.Is(err, uio.ErrUnreadBytes), "ParseOption = %v", err)
+}
+
+func TestOptionGenericStringTruncated(t *testing.T) {
+	defer func(max int) { MaxOptionDataString = max }(MaxOptionDataString)
+	MaxOptionDataString = 4
+
+	opt := &OptionGeneric{OptionCode: 65000, OptionData: []byte{1, 2, 3, 4, 5}}
+	require.Equal(t, "unknown (65000): [1 2 3 4]... (5 bytes)", opt.String())
+}
@@ -12,7 +12,13 @@
 // The handler is a function that takes as input a packet connection, that can be
 // used to reply to the client; a peer address, that identifies the client sending
 // the request, and the DHCPv6 packet itself. Just implement your custom logic in
-// the handler.
+// the handler. Handlers that also need to know the interface a packet was
+// received on can be used with NewServerWithControlMessages instead.
+//
+// A server can serve several interfaces with a single socket: listen on the
+// wildcard address without an interface name, and list the interfaces with the
+// WithInterfaces option. The DHCPv6 multicast groups are then joined on each of
+// them.
 //
 // Optionally, NewServer can receive options that will modify the server object.
 // Some options already exist, for example WithConn. If this option is passed with
@@ -53,54 +59,174 @@
 package server6
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/insomniacslk/dhcp/dhcpv6"
 	"golang.org/x/net/ipv6"
 )
 
+// DefaultReadBufferSize is the size of the buffer used to read incoming
+// packets, unless configured otherwise with WithReadBufferSize. It is large
+// enough to hold any UDP datagram.
+const DefaultReadBufferSize = 65536
+
 // Handler is a type that defines the handler function to be called every time a
 // valid DHCPv6 message is received
+//
+// Each message is handled in its own goroutine, so a Handler may be called
+// concurrently and must be safe for concurrent use. Writing replies to conn
+// from several handlers at once is safe. Use WithMaxConcurrency to bound the
+// number of handlers running at the same time.
 type Handler func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6)
 
+// ControlMessageHandler is like Handler, but additionally receives the IPv6
+// control message of the packet, which carries the index of the interface the
+// packet was received on. cm is nil if the connection does not support control
+// messages.
+type ControlMessageHandler func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6, cm *ipv6.ControlMessage)
+
 // Server represents a DHCPv6 server object
 type Server struct {
-	conn    net.PacketConn
-	handler Handler
-	logger  Logger
+	conn           net.PacketConn
+	handler        ControlMessageHandler
+	logger         Logger
+	readBufferSize int
+
+	// ifnames are the interfaces to join multicast groups on, if
+	// configured with WithInterfaces.
+	ifnames []string
+
+	// started is an atomic bool set to 1 once the serve loop is started.
+	started uint32
+
+	// done is closed when the serve loop exits.
+	done chan struct{}
+
+	// bufPool holds read buffers of readBufferSize bytes.
+	bufPool sync.Pool
+
+	// sem bounds the number of running handlers, if not nil.
+	sem chan struct{}
+
+	// handlers tracks the running handlers.
+	handlers sync.WaitGroup
 }
 
 // Serve starts the DHCPv6 server. The listener will run in background, and can
 // be interrupted with `Server.Close`.
 func (s *Server) Serve() error {
+	return s.ServeContext(context.Background())
+}
+
+// ServeContext is like Serve, but additionally stops serving as soon as ctx is
+// done, in which case the context's error is returned. The listener is closed
+// when ServeContext returns.
+func (s *Server) ServeContext(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return errors.New("server is already serving")
+	}
+	defer close(s.done)
+	defer s.conn.Close()
+
 	s.logger.Printf("Server listening on %s", s.conn.LocalAddr())
 	s.logger.Printf("Ready to handle requests")
 
-	defer s.Close()
+	// Read the receiving interface along with each packet, if the
+	// connection supports it.
+	var p *ipv6.PacketConn
+	if _, ok := s.conn.(net.Conn); ok {
+		p = ipv6.NewPacketConn(s.conn)
+		if err := p.SetControlMessage(ipv6.FlagInterface, true); err != nil {
+			s.logger.Printf("Cannot read control messages, handlers will not know the receiving interface: %v", err)
+			p = nil
+		}
+	}
+
+	// Closing the connection is the only way to unblock ReadFrom.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.conn.Close()
+		case <-stop:
+		}
+	}()
+
 	for {
-		rbuf := make([]byte, 4096) // FIXME this is bad
-		n, peer, err := s.conn.ReadFrom(rbuf)
+		// Parsed messages may reference the read buffer, so it is only
+		// returned to the pool once the handler is done with the message.
+		bp := s.bufPool.Get().(*[]byte)
+		rbuf := *bp
+		var (
+			n    int
+			cm   *ipv6.ControlMessage
+			peer net.Addr
+			err  error
+		)
+		if p != nil {
+			n, cm, peer, err = p.ReadFrom(rbuf)
+		} else {
+			n, peer, err = s.conn.ReadFrom(rbuf)
+		}
 		if err != nil {
+			s.bufPool.Put(bp)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			s.logger.Printf("Error reading from packet conn: %v", err)
 			return err
 		}
 		s.logger.Printf("Handling request from %v", peer)
+		if n == len(rbuf) {
+			s.logger.Printf("Request from %v filled the %d bytes read buffer and may be truncated", peer, n)
+		}
 
 		d, err := dhcpv6.FromBytes(rbuf[:n])
 		if err != nil {
 			s.logger.Printf("Error parsing DHCPv6 request: %v", err)
+			s.bufPool.Put(bp)
 			continue
 		}
 
-		go s.handler(s.conn, peer, d)
+		if s.sem != nil {
+			select {
+			case s.sem <- struct{}{}:
+			case <-ctx.Done():
+				s.bufPool.Put(bp)
+				return ctx.Err()
+			}
+		}
+		s.handlers.Add(1)
+		go func() {
+			defer s.handlers.Done()
+			defer s.bufPool.Put(bp)
+			if s.sem != nil {
+				defer func() { <-s.sem }()
+			}
+			s.handler(s.conn, peer, d, cm)
+		}()
 	}
 }
 
-// Close sends a termination request to the server, and closes the UDP listener
+// Close sends a termination request to the server, and closes the UDP listener.
+// If the server is serving, Close returns only once the serve loop has exited
+// and all running handlers have returned. Close must therefore not be called
+// from a handler.
 func (s *Server) Close() error {
-	return s.conn.Close()
+	err := s.conn.Close()
+	if atomic.LoadUint32(&s.started) == 1 {
+		<-s.done
+		s.handlers.Wait()
+	}
+	return err
 }
 
 // A ServerOpt configures a Server.
@@ -113,6 +239,44 @@ func WithConn(conn net.PacketConn) ServerOpt {
 	}
 }
 
+// WithReadBufferSize configures the size of the buffer used to read incoming
+// packets. Packets larger than the buffer are truncated. A size of zero or less
+// selects DefaultReadBufferSize.
+func WithReadBufferSize(size int) ServerOpt {
+	return func(s *Server) {
+		s.readBufferSize = size
+	}
+}
+
+// WithMaxConcurrency bounds the number of handlers running at the same time to
+// n. Once n handlers are running, the server stops reading packets until one
+// of them returns. A value of zero or less means no bound, which is the
+// default.
+func WithMaxConcurrency(n int) ServerOpt {
+	return func(s *Server) {
+		if n > 0 {
+			s.sem = make(chan struct{}, n)
+		} else {
+			s.sem = nil
+		}
+	}
+}
+
+// WithInterfaces makes the server join its multicast groups on each of the
+// named interfaces, instead of the single interface passed to NewServer, so
+// that one server receives the requests sent on all of them. It also makes a
+// server listening on the wildcard address join the DHCPv6 multicast groups
+// whatever its port is.
+//
+// The interface name passed to NewServer must be empty, as it would bind the
+// socket to that interface only. Use NewServerWithControlMessages to learn
+// which interface each request was received on.
+func WithInterfaces(ifnames ...string) ServerOpt {
+	return func(s *Server) {
+		s.ifnames = ifnames
+	}
+}
+
 // NewServer initializes and returns a new Server object, listening on `addr`.
 // * If `addr` is a multicast group, the group will be additionally joined
 // * If `addr` is the wildcard address on the DHCPv6 server port (`[::]:547), the
@@ -120,18 +284,36 @@ func WithConn(conn net.PacketConn) ServerOpt {
 //   All_DHCP_Servers(`[ff05::1:3]:547`) will be joined.
 // * If `addr` is nil, IPv6 unspec on the DHCP server port is used and the above
 //   behaviour applies
+// * If `WithInterfaces` is used, the groups are joined on each of its
+//   interfaces, and the wildcard address joins them on any port
 // If `WithConn` is used with a non-nil address, `addr` and `ifname` have
 // no effect. In such case, joining the multicast group is the caller's
 // responsibility.
 func NewServer(ifname string, addr *net.UDPAddr, handler Handler, opt ...ServerOpt) (*Server, error) {
+	return NewServerWithControlMessages(ifname, addr, func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6, _ *ipv6.ControlMessage) {
+		handler(conn, peer, m)
+	}, opt...)
+}
+
+// NewServerWithControlMessages is like NewServer, but calls a handler that
+// also receives the IPv6 control message of each packet.
+func NewServerWithControlMessages(ifname string, addr *net.UDPAddr, handler ControlMessageHandler, opt ...ServerOpt) (*Server, error) {
 	s := &Server{
 		handler: handler,
 		logger:  EmptyLogger{},
+		done:    make(chan struct{}),
 	}
 
 	for _, o := range opt {
 		o(s)
 	}
+	if s.readBufferSize <= 0 {
+		s.readBufferSize = DefaultReadBufferSize
+	}
+	s.bufPool.New = func() interface{} {
+		b := make([]byte, s.readBufferSize)
+		return &b
+	}
 	if s.conn != nil {
 		return s, nil
 	}
@@ -143,41 +325,54 @@ func NewServer(ifname string, addr *net.UDPAddr, handler Handler, opt ...ServerO
 		}
 	}
 
-	var (
-		err   error
-		iface *net.Interface
-	)
-	if ifname == "" {
-		iface = nil
-	} else {
-		iface, err = net.InterfaceByName(ifname)
-		if err != nil {
-			return nil, err
+	if ifname != "" && len(s.ifnames) > 0 {
+		return nil, fmt.Errorf("cannot bind to interface %s and join groups on interfaces %v", ifname, s.ifnames)
+	}
+	names := s.ifnames
+	if ifname != "" {
+		names = []string{ifname}
+	}
+	// A nil interface lets the system pick one.
+	ifaces := []*net.Interface{nil}
+	if len(names) > 0 {
+		ifaces = make([]*net.Interface, 0, len(names))
+		for _, name := range names {
+			iface, err := net.InterfaceByName(name)
+			if err != nil {
+				return nil, err
+			}
+			ifaces = append(ifaces, iface)
 		}
 	}
+
+	var groups []net.IP
+	if addr.IP.IsMulticast() {
+		groups = []net.IP{addr.IP}
+	} else if (addr.IP == nil || addr.IP.IsUnspecified()) && (addr.Port == dhcpv6.DefaultServerPort || len(s.ifnames) > 0) {
+		// For wildcard addresses on the correct port, or when serving a
+		// list of interfaces, listen on both multicast addresses defined in
+		// the RFC as a "default" behaviour
+		groups = []net.IP{dhcpv6.AllDHCPRelayAgentsAndServers, dhcpv6.AllDHCPServers}
+	}
+
 	// no connection provided by the user, create a new one
+	var err error
 	s.conn, err = NewIPv6UDPConn(ifname, addr)
 	if err != nil {
 		return nil, err
 	}
 
 	p := ipv6.NewPacketConn(s.conn)
-	if addr.IP.IsMulticast() {
-		if err := p.JoinGroup(iface, addr); err != nil {
-			return nil, err
-		}
-	} else if (addr.IP == nil || addr.IP.IsUnspecified()) && addr.Port == dhcpv6.DefaultServerPort {
-		// For wildcard addresses on the correct port, listen on both multicast
-		// addresses defined in the RFC as a "default" behaviour
-		for _, g := range []net.IP{dhcpv6.AllDHCPRelayAgentsAndServers, dhcpv6.AllDHCPServers} {
+	for _, iface := range ifaces {
+		for _, g := range groups {
 			group := net.UDPAddr{
 				IP:   g,
-				Port: dhcpv6.DefaultServerPort,
+				Port: addr.Port,
 			}
 			if err := p.JoinGroup(iface, &group); err != nil {
+				s.conn.Close()
 				return nil, err
 			}
-
 		}
 	}
 
@@ -2,14 +2,21 @@ package server6
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/insomniacslk/dhcp/dhcpv6"
 	"github.com/insomniacslk/dhcp/dhcpv6/nclient6"
 	"github.com/insomniacslk/dhcp/interfaces"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv6"
 )
 
 // Turns a connected UDP conn into an "unconnected" UDP conn.
@@ -76,3 +83,395 @@ func TestServer(t *testing.T) {
 	_, err = c.Solicit(context.Background(), dhcpv6.WithRapidCommit)
 	require.NoError(t, err)
 }
+
+func TestServerControlMessage(t *testing.T) {
+	loopbacks, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(loopbacks))
+
+	received := make(chan *ipv6.ControlMessage, 1)
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6, cm *ipv6.ControlMessage) {
+		received <- cm
+	}
+	laddr := &net.UDPAddr{
+		IP:   net.ParseIP("::1"),
+		Port: 0,
+	}
+	s, err := NewServerWithControlMessages("", laddr, handler)
+	require.NoError(t, err)
+	defer s.Close()
+	go func() {
+		_ = s.Serve()
+	}()
+
+	conn, err := net.DialUDP("udp6", nil, s.conn.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer conn.Close()
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	_, err = conn.Write(msg.ToBytes())
+	require.NoError(t, err)
+
+	select {
+	case cm := <-received:
+		require.NotNil(t, cm)
+		require.Equal(t, loopbacks[0].Index, cm.IfIndex)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
+
+func TestServerControlMessageUnsupported(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+
+	received := make(chan *ipv6.ControlMessage, 1)
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6, cm *ipv6.ControlMessage) {
+		received <- cm
+	}
+	s, err := NewServerWithControlMessages("", nil, handler,
+		WithConn(&fakePacketConn{packets: [][]byte{msg.ToBytes()}}))
+	require.NoError(t, err)
+	require.Equal(t, io.EOF, s.Serve())
+	require.Nil(t, <-received)
+}
+
+// fakePacketConn hands out a fixed list of packets and then fails with io.EOF.
+type fakePacketConn struct {
+	net.PacketConn
+	packets [][]byte
+}
+
+func (f *fakePacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	if len(f.packets) == 0 {
+		return 0, nil, io.EOF
+	}
+	n := copy(b, f.packets[0])
+	f.packets = f.packets[1:]
+	return n, &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: dhcpv6.DefaultClientPort}, nil
+}
+
+func (f *fakePacketConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: net.IPv6unspecified, Port: dhcpv6.DefaultServerPort}
+}
+
+func (f *fakePacketConn) Close() error {
+	return nil
+}
+
+type recordingLogger struct {
+	EmptyLogger
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) Printf(format string, v ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf(format, v...))
+}
+
+func TestServerReadBufferSize(t *testing.T) {
+	msg, err := dhcpv6.NewMessage(dhcpv6.WithOption(&dhcpv6.OptionGeneric{
+		OptionCode: dhcpv6.OptionVendorOpts,
+		OptionData: make([]byte, 8000),
+	}))
+	require.NoError(t, err)
+	packet := msg.ToBytes()
+
+	t.Run("default", func(t *testing.T) {
+		received := make(chan dhcpv6.DHCPv6, 1)
+		handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+			received <- m
+		}
+		logger := &recordingLogger{}
+		s, err := NewServer("", nil, handler,
+			WithConn(&fakePacketConn{packets: [][]byte{packet}}),
+			WithLogger(logger))
+		require.NoError(t, err)
+		require.Equal(t, io.EOF, s.Serve())
+
+		select {
+		case m := <-received:
+			require.Equal(t, packet, m.ToBytes())
+		case <-time.After(time.Second):
+			t.Fatal("handler was not called")
+		}
+		for _, l := range logger.lines {
+			require.NotContains(t, l, "truncated")
+		}
+	})
+
+	t.Run("too small", func(t *testing.T) {
+		handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {}
+		logger := &recordingLogger{}
+		s, err := NewServer("", nil, handler,
+			WithConn(&fakePacketConn{packets: [][]byte{packet}}),
+			WithLogger(logger),
+			WithReadBufferSize(4096))
+		require.NoError(t, err)
+		require.Equal(t, io.EOF, s.Serve())
+
+		var warned bool
+		for _, l := range logger.lines {
+			if strings.Contains(l, "truncated") {
+				warned = true
+			}
+		}
+		require.True(t, warned)
+	})
+}
+
+func TestServerReadBufferReuse(t *testing.T) {
+	// An unassigned option code, parsed as an OptionGeneric.
+	const testOptionCode = dhcpv6.OptionCode(65000)
+
+	var packets [][]byte
+	for _, b := range []byte{0xaa, 0xbb} {
+		data := make([]byte, 100)
+		for i := range data {
+			data[i] = b
+		}
+		msg, err := dhcpv6.NewMessage(dhcpv6.WithOption(&dhcpv6.OptionGeneric{
+			OptionCode: testOptionCode,
+			OptionData: data,
+		}))
+		require.NoError(t, err)
+		packets = append(packets, msg.ToBytes())
+	}
+
+	// The first handler holds on to its message until the second packet
+	// has been read into a pooled buffer and handled.
+	var (
+		mu       sync.Mutex
+		received [][]byte
+		second   = make(chan struct{})
+		done     = make(chan struct{}, 2)
+	)
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+		if m.GetOneOption(testOptionCode).ToBytes()[0] == 0xaa {
+			<-second
+		} else {
+			close(second)
+		}
+		mu.Lock()
+		received = append(received, m.ToBytes())
+		mu.Unlock()
+		done <- struct{}{}
+	}
+	s, err := NewServer("", nil, handler,
+		WithConn(&fakePacketConn{packets: packets}),
+		WithReadBufferSize(1024))
+	require.NoError(t, err)
+	require.Equal(t, io.EOF, s.Serve())
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler was not called")
+		}
+	}
+	require.ElementsMatch(t, packets, received)
+}
+
+func TestServerConcurrentHandlers(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	packet := msg.ToBytes()
+
+	for _, tt := range []struct {
+		name    string
+		opts    []ServerOpt
+		running int
+	}{
+		{name: "unbounded", running: 2},
+		{name: "max concurrency 1", opts: []ServerOpt{WithMaxConcurrency(1)}, running: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+			handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+				started <- struct{}{}
+				<-release
+			}
+			opts := append([]ServerOpt{WithConn(&fakePacketConn{packets: [][]byte{packet, packet}})}, tt.opts...)
+			s, err := NewServer("", nil, handler, opts...)
+			require.NoError(t, err)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- s.Serve()
+			}()
+
+			for i := 0; i < tt.running; i++ {
+				select {
+				case <-started:
+				case <-time.After(time.Second):
+					t.Fatalf("only %d of %d handlers are running", i, tt.running)
+				}
+			}
+			select {
+			case <-started:
+				t.Fatalf("more than %d handlers are running", tt.running)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(release)
+			select {
+			case err := <-errCh:
+				require.Equal(t, io.EOF, err)
+			case <-time.After(time.Second):
+				t.Fatal("Serve did not return")
+			}
+		})
+	}
+}
+
+func TestServerInterfaces(t *testing.T) {
+	all, err := net.Interfaces()
+	require.NoError(t, err)
+	var ifnames []string
+	var ifaces []net.Interface
+	for _, iface := range all {
+		if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagMulticast != 0 && iface.Flags&net.FlagLoopback == 0 {
+			ifnames = append(ifnames, iface.Name)
+			ifaces = append(ifaces, iface)
+		}
+	}
+	if len(ifaces) == 0 {
+		t.Skip("no multicast interface")
+	}
+
+	received := make(chan *ipv6.ControlMessage, 2*len(ifaces))
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6, cm *ipv6.ControlMessage) {
+		received <- cm
+	}
+	laddr := &net.UDPAddr{IP: net.IPv6unspecified, Port: 0}
+	s, err := NewServerWithControlMessages("", laddr, handler, WithInterfaces(ifnames...))
+	require.NoError(t, err)
+	defer s.Close()
+	go func() {
+		_ = s.Serve()
+	}()
+	port := s.conn.LocalAddr().(*net.UDPAddr).Port
+
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	for i := range ifaces {
+		iface := &ifaces[i]
+		conn, err := net.ListenPacket("udp6", "[::]:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		p := ipv6.NewPacketConn(conn)
+		require.NoError(t, p.SetMulticastInterface(iface))
+		require.NoError(t, p.SetMulticastLoopback(true))
+
+		for _, group := range []net.IP{dhcpv6.AllDHCPRelayAgentsAndServers, dhcpv6.AllDHCPServers} {
+			dst := &net.UDPAddr{IP: group, Port: port, Zone: iface.Name}
+			_, err := conn.WriteTo(msg.ToBytes(), dst)
+			require.NoError(t, err)
+
+			select {
+			case cm := <-received:
+				require.NotNil(t, cm)
+				require.Equal(t, iface.Index, cm.IfIndex)
+				require.True(t, group.Equal(cm.Dst), "got packet for %v, want %v", cm.Dst, group)
+			case <-time.After(time.Second):
+				t.Fatalf("no packet received for %v on %s", group, iface.Name)
+			}
+		}
+	}
+}
+
+func TestServerInterfacesWithInterfaceName(t *testing.T) {
+	_, err := NewServer("lo", nil, func(net.PacketConn, net.Addr, dhcpv6.DHCPv6) {}, WithInterfaces("eth0"))
+	require.Error(t, err)
+}
+
+func TestServeContextCancel(t *testing.T) {
+	laddr := &net.UDPAddr{
+		IP:   net.ParseIP("::1"),
+		Port: 0,
+	}
+	s, err := NewServer("", laddr, func(net.PacketConn, net.Addr, dhcpv6.DHCPv6) {})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.ServeContext(ctx)
+	}()
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Fatal("ServeContext did not return after cancellation")
+	}
+	require.Error(t, s.Serve(), "a server cannot serve twice")
+}
+
+func TestCloseWaitsForServe(t *testing.T) {
+	laddr := &net.UDPAddr{
+		IP:   net.ParseIP("::1"),
+		Port: 0,
+	}
+	s, err := NewServer("", laddr, func(net.PacketConn, net.Addr, dhcpv6.DHCPv6) {})
+	require.NoError(t, err)
+
+	go func() {
+		_ = s.Serve()
+	}()
+	// Wait for the serve loop to start.
+	for atomic.LoadUint32(&s.started) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_ = s.Close()
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("Close returned before the serve loop exited")
+	}
+}
+
+func TestCloseWaitsForHandlers(t *testing.T) {
+	msg, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished int32
+	handler := func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+		close(started)
+		<-release
+		atomic.StoreInt32(&finished, 1)
+	}
+	s, err := NewServer("", nil, handler,
+		WithConn(&fakePacketConn{packets: [][]byte{msg.ToBytes()}}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve()
+	}()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		_ = s.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a handler was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return after the handler finished")
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&finished))
+}
@@ -2,6 +2,7 @@ package dhcpv6
 
 import (
 	"fmt"
+	"sort"
 )
 
 // TransactionID is a DHCPv6 Transaction ID defined by RFC 3315, Section 6.
@@ -50,6 +51,23 @@ func (m MessageType) String() string {
 	return fmt.Sprintf("unknown (%d)", m)
 }
 
+// IsValid reports whether m is one of the message types defined above.
+// MessageTypeNone is not valid.
+func (m MessageType) IsValid() bool {
+	_, ok := messageTypeToStringMap[m]
+	return ok
+}
+
+// KnownMessageTypes returns all valid message types, sorted by value.
+func KnownMessageTypes() []MessageType {
+	types := make([]MessageType, 0, len(messageTypeToStringMap))
+	for m := range messageTypeToStringMap {
+		types = append(types, m)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
 // messageTypeToStringMap contains the mapping of MessageTypes to
 // human-readable strings.
 var messageTypeToStringMap = map[MessageType]string{
@@ -0,0 +1,18 @@
+// Package truncate formats raw option data for String methods.
+package truncate
+
+import "fmt"
+
+// DefaultMax is the default number of bytes printed by the String methods of
+// generic options in the dhcpv4 and dhcpv6 packages.
+const DefaultMax = 256
+
+// Bytes formats data like fmt's %v verb. If max is positive and data is longer
+// than max bytes, only the first max bytes are printed, followed by the total
+// length.
+func Bytes(data []byte, max int) string {
+	if max > 0 && len(data) > max {
+		return fmt.Sprintf("%v... (%d bytes)", data[:max], len(data))
+	}
+	return fmt.Sprintf("%v", data)
+}
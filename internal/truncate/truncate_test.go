@@ -0,0 +1,15 @@
+package truncate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytes(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5}
+	require.Equal(t, "[1 2 3 4 5]", Bytes(data, 5))
+	require.Equal(t, "[1 2 3 4]... (5 bytes)", Bytes(data, 4))
+	require.Equal(t, "[1 2 3 4 5]", Bytes(data, 0))
+	require.Equal(t, "[]", Bytes(nil, 4))
+}
@@ -74,7 +74,9 @@ func (l *Labels) FromBytes(data []byte) error {
 	if err != nil {
 		return err
 	}
-	l.original = data
+	// Copy data, so that the caller can reuse it. data[:0:0] keeps an empty
+	// but non-nil original, which ToBytes tells apart from no original.
+	l.original = append(data[:0:0], data...)
 	l.Labels = labs
 	return nil
 }
@@ -100,6 +102,7 @@ func labelsFromBytes(buf []byte) ([]string, error) {
 		pos, oldPos     int
 		label           string
 		handlingPointer bool
+		pointers        int
 	)
 
 	for {
@@ -118,21 +121,31 @@ func labelsFromBytes(buf []byte) ([]string, error) {
 		if length == 0 {
 			labels = append(labels, label)
 			label = ""
+			// Each name gets its own pointer budget.
+			pointers = 0
 			if handlingPointer {
 				pos = oldPos
 				handlingPointer = false
 			}
 		} else if length&0xc0 == 0xc0 {
-			// compression pointer
-			if handlingPointer {
-				return nil, errors.New("rfc1035label: cannot handle nested pointers")
-			}
-			handlingPointer = true
+			// compression pointer, as defined by RFC 1035 Section 4.1.4.
 			if pos+1 > len(buf) {
 				return nil, errors.New("rfc1035label: pointer buffer too short")
 			}
+			// A chain of pointers within one name cannot be longer
+			// than the number of pointers fitting in buf without a
+			// loop.
+			pointers++
+			if pointers > len(buf)/2 {
+				return nil, errors.New("rfc1035label: compression pointer loop")
+			}
 			off := int(buf[pos-1]&^0xc0)<<8 + int(buf[pos])
-			oldPos = pos + 1
+			// Only the outermost pointer decides where parsing
+			// resumes.
+			if !handlingPointer {
+				handlingPointer = true
+				oldPos = pos + 1
+			}
 			pos = off
 		} else {
 			if pos+length > len(buf) {
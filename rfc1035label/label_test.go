@@ -1,6 +1,7 @@
 package rfc1035label
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -109,6 +110,22 @@ func TestCompressedLabel(t *testing.T) {
 	require.Equal(t, len(data), labels.Length())
 }
 
+func TestLabelsFromBytesCopiesData(t *testing.T) {
+	data := []byte{
+		3, 'f', 'o', 'o', 0,
+		3, 'b', 'a', 'r', 192, 0,
+	}
+	want := append([]byte(nil), data...)
+
+	labels, err := FromBytes(data)
+	require.NoError(t, err)
+	// Reusing the buffer does not change the serialized labels.
+	for i := range data {
+		data[i] = 0
+	}
+	require.Equal(t, want, labels.ToBytes())
+}
+
 func TestShortCompressedLabel(t *testing.T) {
 	data := []byte{
 		// slackware.it
@@ -140,6 +157,65 @@ func TestNestedCompressedLabel(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestChainedCompressedLabel(t *testing.T) {
+	data := []byte{
+		// slackware.it
+		9, 's', 'l', 'a', 'c', 'k', 'w', 'a', 'r', 'e',
+		2, 'i', 't',
+		0,
+		// insomniac.slackware.it
+		9, 'i', 'n', 's', 'o', 'm', 'n', 'i', 'a', 'c',
+		192, 0,
+		// mail.insomniac.slackware.it, pointing at the pointer above
+		4, 'm', 'a', 'i', 'l',
+		192, 14,
+		// www.slackware.it
+		3, 'w', 'w', 'w',
+		192, 0,
+	}
+	expected := []string{
+		"slackware.it",
+		"insomniac.slackware.it",
+		"mail.insomniac.slackware.it",
+		"www.slackware.it",
+	}
+
+	labels, err := FromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, labels.Labels)
+}
+
+func TestManyChainedCompressedLabels(t *testing.T) {
+	// Each name is one label and a pointer to the previous name, as in
+	// search lists of nested domains.
+	data := []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+	expected := []string{"example.com"}
+	prev := 0
+	for i := 0; i < 12; i++ {
+		start := len(data)
+		data = append(data, 2, 's', byte('a'+i), 192, byte(prev))
+		expected = append(expected, fmt.Sprintf("s%c.%s", 'a'+i, expected[len(expected)-1]))
+		prev = start
+	}
+
+	labels, err := FromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, labels.Labels)
+	require.Equal(t, data, labels.ToBytes())
+}
+
+func TestCompressedLabelLoop(t *testing.T) {
+	for _, data := range [][]byte{
+		// A pointer to itself.
+		{192, 0},
+		// Two pointers to each other.
+		{3, 'f', 'o', 'o', 192, 6, 192, 4},
+	} {
+		_, err := FromBytes(data)
+		require.Error(t, err, "data %v", data)
+	}
+}
+
 func FuzzLabel(f *testing.F) {
 
 	f.Add([]byte{0x5, 0xaa, 0xbb})